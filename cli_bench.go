@@ -0,0 +1,128 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/henilmalaviya/gol/grid"
+	"github.com/henilmalaviya/golw/game"
+	"github.com/henilmalaviya/golw/util"
+)
+
+// benchResult is the outcome of ticking one world with a worker count.
+type benchResult struct {
+	workers   int
+	durations []time.Duration
+	total     time.Duration
+	hash      uint64
+	final     int
+}
+
+func runBench(args []string) error {
+	flags := flag.NewFlagSet("bench", flag.ExitOnError)
+	flags.Usage = usageFor(flags, "bench [flags]", "Tick a random world with each worker count and report the tick times.\nEvery run starts from the same world and must end in the same state.")
+	size := flags.Int("size", 512, "width and height of the random world")
+	density := flags.Float64("density", 0.35, "fraction of live cells in the random world")
+	generations := flags.Int("generations", 200, "generations to run")
+	workerList := flags.String("workers", "1,"+strconv.Itoa(runtime.NumCPU()), "comma separated worker counts to compare")
+	seed := flags.Int64("seed", 1, "seed of the random world")
+	flags.Parse(args)
+
+	var workers []int
+	for _, part := range strings.Split(*workerList, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid worker count %q", part)
+		}
+		if !slices.Contains(workers, n) {
+			workers = append(workers, n)
+		}
+	}
+	if *size < 1 || *generations < 1 {
+		return fmt.Errorf("size and generations must be positive")
+	}
+
+	fill := game.RandomFill{
+		Bounds:  *grid.NewRectangle(-*size/2, -*size/2, *size-*size/2-1, *size-*size/2-1),
+		Density: *density,
+		Seed:    *seed,
+	}
+	if err := fill.Validate(); err != nil {
+		return err
+	}
+
+	// The runs log every fill and period they find, which is noise here.
+	util.SetLogLevel("warn")
+
+	fmt.Printf("Benchmarking %d generations of a %dx%d world at density %.2f (seed %d)\n\n", *generations, *size, *size, *density, *seed)
+
+	results := make([]benchResult, len(workers))
+	for i, n := range workers {
+		results[i] = bench(fill, n, *generations)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "WORKERS\tGEN/S\tMEAN MS\tP50 MS\tP99 MS\tMAX MS\tSPEEDUP\t")
+	for _, r := range results {
+		sorted := slices.Clone(r.durations)
+		slices.Sort(sorted)
+		mean := r.total / time.Duration(len(sorted))
+		fmt.Fprintf(w, "%d\t%.1f\t%.2f\t%.2f\t%.2f\t%.2f\t%.2fx\t\n",
+			r.workers,
+			float64(len(sorted))/r.total.Seconds(),
+			ms(mean),
+			ms(percentile(sorted, 0.5)),
+			ms(percentile(sorted, 0.99)),
+			ms(sorted[len(sorted)-1]),
+			results[0].total.Seconds()/r.total.Seconds(),
+		)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nFinal population: %d\n", results[0].final)
+	for _, r := range results[1:] {
+		if r.hash != results[0].hash || r.final != results[0].final {
+			return fmt.Errorf("%d workers ended in a different state than %d", r.workers, results[0].workers)
+		}
+	}
+	return nil
+}
+
+// bench ticks a fresh world generations times with the given worker count.
+func bench(fill game.RandomFill, workers, generations int) benchResult {
+	gm := game.NewManager()
+	gm.SetTickWorkers(workers)
+	gm.SetHistorySize(0)
+	gm.RandomFill(fill, game.Author{})
+
+	result := benchResult{workers: workers, durations: make([]time.Duration, generations)}
+	for i := range generations {
+		start := time.Now()
+		gm.Tick()
+		result.durations[i] = time.Since(start)
+		result.total += result.durations[i]
+	}
+
+	cells := gm.GetGame().GetGrid().GetCells()
+	result.hash = game.StateHash(cells)
+	result.final = len(cells)
+	return result
+}
+
+// percentile returns the duration at fraction p of sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	return sorted[min(int(p*float64(len(sorted))), len(sorted)-1)]
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/henilmalaviya/gol/grid"
+	"github.com/henilmalaviya/golw/env"
+	"github.com/henilmalaviya/golw/game"
+	"github.com/henilmalaviya/golw/util"
+)
+
+func runExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	flags.Usage = usageFor(flags, "export [flags] [snapshot]", "Write the cells of a snapshot, the latest in the save directory by default.\nThe json format is the [[x, y], ...] array taken by set_cells.")
+	format := flags.String("format", "rle", "output format: rle or json")
+	region := flags.String("region", "", "only export the cells in x1,y1,x2,y2")
+	out := flags.String("o", "", "file to write, stdout by default")
+	applySettings := settingFlags(flags, "SAVE_DIR")
+	flags.Parse(args)
+	if err := applySettings(); err != nil {
+		return err
+	}
+	if *format != "rle" && *format != "json" {
+		return fmt.Errorf("unknown format %q, expected rle or json", *format)
+	}
+
+	path, err := optionalArg(flags)
+	if err != nil {
+		return err
+	}
+	snap, path, err := openSnapshot(path)
+	if err != nil {
+		return err
+	}
+
+	cells := snap.Cells()
+	if *region != "" {
+		rect, err := util.ParseRectangle(*region)
+		if err != nil {
+			return err
+		}
+		inside := cells[:0]
+		for _, c := range cells {
+			if rect.PointInside(c.X, c.Y) {
+				inside = append(inside, c)
+			}
+		}
+		cells = inside
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch *format {
+	case "json":
+		coords := make([][2]int, len(cells))
+		for i, c := range cells {
+			coords[i] = [2]int{c.X, c.Y}
+		}
+		err = json.NewEncoder(w).Encode(coords)
+	default:
+		err = game.EncodeRLE(w, cells, fmt.Sprintf("Generation %d of %s", snap.Stats.Generation, path))
+	}
+	if err != nil {
+		return err
+	}
+
+	util.GetLogger().Info("Exported cells", "count", len(cells), "format", *format)
+	return nil
+}
+
+func runImport(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	flags.Usage = usageFor(flags, "import [flags] pattern.rle", "Add an RLE pattern to the latest snapshot and save the result as a new snapshot,\nwhich the server loads on its next start. Stop the server first, or its next\nsave replaces the import.")
+	at := flags.String("at", "", "x,y to place the pattern's top left corner at, its #R position by default")
+	from := flags.String("snapshot", "", "snapshot to add the pattern to, the latest by default")
+	replace := flags.Bool("replace", false, "start from an empty world instead of a snapshot")
+	applySettings := settingFlags(flags, "SAVE_DIR", "WORLD_TOPOLOGY", "WORLD_BOUNDS")
+	flags.Parse(args)
+	if err := applySettings(); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("expected one pattern file, got %d", flags.NArg())
+	}
+
+	f, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	cells, err := game.DecodeRLE(f)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("%s: %w", flags.Arg(0), err)
+	}
+
+	if *at != "" && len(cells) > 0 {
+		x, y, err := parsePoint(*at)
+		if err != nil {
+			return err
+		}
+		gr := grid.NewGridFromCells(cells...)
+		bounds := gr.Bounds()
+		gr.Translate(x-bounds.X1, y-bounds.Y1)
+		cells = gr.GetCells()
+	}
+
+	topology := loadTopology()
+	for _, c := range cells {
+		if err := topology.Check(c.X, c.Y); err != nil {
+			return fmt.Errorf("pattern does not fit in the world: %w", err)
+		}
+	}
+
+	snap := &game.Snapshot{}
+	if !*replace {
+		base, path, err := openSnapshot(*from)
+		if err != nil {
+			return err
+		}
+		snap = base
+		util.GetLogger().Info("Adding pattern to snapshot", "snapshot", path, "generation", snap.Stats.Generation)
+	}
+
+	gr := grid.NewGridFromXY(snap.Grid)
+	for _, c := range cells {
+		gr.SetCell(c.X, c.Y)
+	}
+	setSnapshotCells(snap, gr)
+	snap.Version = game.SnapshotVersion
+	snap.Timestamp = time.Now()
+
+	path := env.Get().SaveDirectory + "/" + game.SnapshotFileName(snap.Timestamp)
+	if err := game.WriteSnapshot(path, snap); err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d cells into %s\n", len(cells), path)
+	return nil
+}
+
+// parsePoint parses a point written as "x,y".
+func parsePoint(value string) (int, int, error) {
+	var x, y int
+	if _, err := fmt.Sscanf(value, "%d,%d", &x, &y); err != nil {
+		return 0, 0, fmt.Errorf("point %q must have the form x,y", value)
+	}
+	return x, y, nil
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/henilmalaviya/gol/grid"
+	"github.com/henilmalaviya/golw/env"
+	"github.com/henilmalaviya/golw/game"
+)
+
+func snapshotUsage() {
+	fmt.Fprintf(os.Stderr, `Usage: golw snapshot <command> [flags]
+
+Commands:
+  list                 list the snapshots in the save directory
+  inspect [file]       describe a snapshot, the latest by default
+  convert [file]       rewrite a snapshot in the current format
+  prune                remove old or unreadable snapshots
+
+Run golw snapshot <command> -h for the flags of a command.
+`)
+}
+
+func runSnapshot(args []string) error {
+	if len(args) == 0 {
+		snapshotUsage()
+		return errors.New("missing snapshot command")
+	}
+
+	switch args[0] {
+	case "list":
+		return runSnapshotList(args[1:])
+	case "inspect":
+		return runSnapshotInspect(args[1:])
+	case "convert":
+		return runSnapshotConvert(args[1:])
+	case "prune":
+		return runSnapshotPrune(args[1:])
+	case "help", "-h", "-help", "--help":
+		snapshotUsage()
+		return nil
+	default:
+		snapshotUsage()
+		return fmt.Errorf("unknown snapshot command %q", args[0])
+	}
+}
+
+// openSnapshot reads the snapshot at path, or the latest one in the save
+// directory when path is empty.
+func openSnapshot(path string) (*game.Snapshot, string, error) {
+	if path == "" {
+		files, err := game.ListSnapshotFiles(env.Get().SaveDirectory)
+		if err != nil {
+			return nil, "", err
+		}
+		if len(files) == 0 {
+			return nil, "", fmt.Errorf("no snapshot in %s", env.Get().SaveDirectory)
+		}
+		path = files[0].Path
+	}
+
+	snap, err := game.ReadSnapshot(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", path, err)
+	}
+	return snap, path, nil
+}
+
+// optionalArg returns the only positional argument of a command, or "" if
+// there is none.
+func optionalArg(flags *flag.FlagSet) (string, error) {
+	switch flags.NArg() {
+	case 0:
+		return "", nil
+	case 1:
+		return flags.Arg(0), nil
+	default:
+		return "", fmt.Errorf("expected at most one file, got %d", flags.NArg())
+	}
+}
+
+// setSnapshotCells replaces the cells of a snapshot and updates its
+// population and bounds.
+func setSnapshotCells(snap *game.Snapshot, gr *grid.Grid) {
+	snap.Grid = gr.GetLiveCellCoordinates()
+	snap.Stats.Population = gr.Population()
+	snap.Stats.Bounds = nil
+	if gr.Population() > 0 {
+		bounds := gr.Bounds()
+		nested := bounds.ToNestedArray()
+		snap.Stats.Bounds = &nested
+	}
+}
+
+func runSnapshotList(args []string) error {
+	flags := flag.NewFlagSet("snapshot list", flag.ExitOnError)
+	flags.Usage = usageFor(flags, "snapshot list [flags]", "List the snapshots in the save directory, newest first.")
+	applySettings := settingFlags(flags, "SAVE_DIR")
+	flags.Parse(args)
+	if err := applySettings(); err != nil {
+		return err
+	}
+
+	files, err := game.ListSnapshotFiles(env.Get().SaveDirectory)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "FILE\tTAKEN\tVERSION\tGENERATION\tPOPULATION\tSIZE")
+	for _, f := range files {
+		snap, err := game.ReadSnapshot(f.Path)
+		if err != nil {
+			fmt.Fprintf(w, "%s\t%s\t-\t-\t-\t%d\n", filepath.Base(f.Path), "unreadable", f.Size)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\n", filepath.Base(f.Path), snap.Timestamp.Format(time.DateTime), snap.Version, snap.Stats.Generation, len(snap.Grid), f.Size)
+	}
+	return w.Flush()
+}
+
+func runSnapshotInspect(args []string) error {
+	flags := flag.NewFlagSet("snapshot inspect", flag.ExitOnError)
+	flags.Usage = usageFor(flags, "snapshot inspect [flags] [file]", "Describe a snapshot, the latest in the save directory by default.")
+	census := flags.Bool("census", false, "also count the objects in the snapshot")
+	applySettings := settingFlags(flags, "SAVE_DIR")
+	flags.Parse(args)
+	if err := applySettings(); err != nil {
+		return err
+	}
+
+	path, err := optionalArg(flags)
+	if err != nil {
+		return err
+	}
+	snap, path, err := openSnapshot(path)
+	if err != nil {
+		return err
+	}
+
+	gr := grid.NewGridFromXY(snap.Grid)
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "File:\t%s\n", path)
+	fmt.Fprintf(w, "Version:\t%d\n", snap.Version)
+	fmt.Fprintf(w, "Taken:\t%s\n", snap.Timestamp.Format(time.RFC3339))
+	fmt.Fprintf(w, "Generation:\t%d\n", snap.Stats.Generation)
+	fmt.Fprintf(w, "Population:\t%d\n", gr.Population())
+	if gr.Population() > 0 {
+		bounds := gr.Bounds()
+		fmt.Fprintf(w, "Bounds:\t%v\n", bounds.ToNestedArray())
+	}
+	fmt.Fprintf(w, "Births:\t%d\n", snap.Stats.BirthCount)
+	fmt.Fprintf(w, "Deaths:\t%d\n", snap.Stats.DeathCount)
+	if snap.Stats.Period > 0 {
+		fmt.Fprintf(w, "Period:\t%d since generation %d\n", snap.Stats.Period, snap.Stats.StabilizedAt)
+	}
+	fmt.Fprintf(w, "Zones:\t%d\n", len(snap.Zones))
+	fmt.Fprintf(w, "Scheduled edits:\t%d\n", len(snap.Schedule))
+
+	if *census {
+		counts := game.TakeCensus(gr.GetCells())
+		names := make([]string, 0, len(counts))
+		for name := range counts {
+			names = append(names, name)
+		}
+		slices.SortFunc(names, func(a, b string) int {
+			if counts[a] != counts[b] {
+				return counts[b] - counts[a]
+			}
+			return cmp.Compare(a, b)
+		})
+
+		fmt.Fprintln(w, "Census:")
+		for _, name := range names {
+			fmt.Fprintf(w, "  %s\t%d\n", name, counts[name])
+		}
+	}
+	return w.Flush()
+}
+
+func runSnapshotConvert(args []string) error {
+	flags := flag.NewFlagSet("snapshot convert", flag.ExitOnError)
+	flags.Usage = usageFor(flags, "snapshot convert [flags] [file]", fmt.Sprintf("Rewrite a snapshot as version %d, the latest in the save directory by default.\nCells are deduplicated and the population and bounds recomputed.", game.SnapshotVersion))
+	out := flags.String("o", "", "file to write, the input file by default")
+	applySettings := settingFlags(flags, "SAVE_DIR")
+	flags.Parse(args)
+	if err := applySettings(); err != nil {
+		return err
+	}
+
+	path, err := optionalArg(flags)
+	if err != nil {
+		return err
+	}
+	snap, path, err := openSnapshot(path)
+	if err != nil {
+		return err
+	}
+	if snap.Version > game.SnapshotVersion {
+		return fmt.Errorf("%s is version %d, newer than this build's %d", path, snap.Version, game.SnapshotVersion)
+	}
+
+	from := snap.Version
+	setSnapshotCells(snap, grid.NewGridFromXY(snap.Grid))
+	snap.Version = game.SnapshotVersion
+
+	if *out == "" {
+		*out = path
+	}
+	if err := game.WriteSnapshot(*out, snap); err != nil {
+		return err
+	}
+	fmt.Printf("Converted %s from version %d to %d into %s\n", path, from, snap.Version, *out)
+	return nil
+}
+
+func runSnapshotPrune(args []string) error {
+	flags := flag.NewFlagSet("snapshot prune", flag.ExitOnError)
+	flags.Usage = usageFor(flags, "snapshot prune [flags]", "Remove the oldest snapshots beyond -keep, and unreadable ones with -invalid.")
+	keep := flags.Int("keep", -1, "snapshots to keep, MAX_SAVE_FILES by default (0 keeps all)")
+	invalid := flags.Bool("invalid", false, "also remove files that are not readable snapshots")
+	dryRun := flags.Bool("dry-run", false, "only print what would be removed")
+	applySettings := settingFlags(flags, "SAVE_DIR")
+	flags.Parse(args)
+	if err := applySettings(); err != nil {
+		return err
+	}
+	if *keep < 0 {
+		*keep = env.Get().MaxSavesFiles
+	}
+
+	files, err := game.ListSnapshotFiles(env.Get().SaveDirectory)
+	if err != nil {
+		return err
+	}
+
+	var remove []string
+	kept := 0
+	for _, f := range files {
+		if *invalid {
+			if _, err := game.ReadSnapshot(f.Path); err != nil {
+				remove = append(remove, f.Path)
+				continue
+			}
+		}
+		if *keep > 0 && kept >= *keep {
+			remove = append(remove, f.Path)
+			continue
+		}
+		kept++
+	}
+
+	for _, path := range remove {
+		if *dryRun {
+			fmt.Printf("Would remove %s\n", path)
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		fmt.Printf("Removed %s\n", path)
+	}
+	fmt.Printf("Kept %d of %d files\n", len(files)-len(remove), len(files))
+	return nil
+}
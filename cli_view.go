@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+
+	"github.com/henilmalaviya/golw/client"
+	"github.com/henilmalaviya/golw/env"
+	"github.com/henilmalaviya/golw/tui"
+)
+
+func runView(args []string) error {
+	flags := flag.NewFlagSet("view", flag.ExitOnError)
+	flags.Usage = usageFor(flags, "view [flags]", "Watch a running server in the terminal, pan around and edit cells.")
+	url := flags.String("url", "", "WebSocket URL of the server, ws://localhost:<PORT><WS_ENDPOINT> by default")
+	name := flags.String("name", "", "name shown to other users")
+	color := flags.String("color", "", "color shown to other users, as #rrggbb")
+	token := flags.String("token", "", "admin token")
+	flags.Parse(args)
+
+	if *url == "" {
+		*url = "ws://localhost:" + env.Get().Port + env.Get().WSEndpoint
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	return tui.Run(ctx, *url, client.Options{Name: *name, Color: *color, Token: *token})
+}
@@ -0,0 +1,366 @@
+// Package client is a Go client for the game server's WebSocket protocol.
+//
+// A Client sends commands with an id and waits for the reply carrying it,
+// so it can be used from several goroutines at once. Observe events arrive
+// on the Events channel. When the connection drops the client reconnects,
+// authenticates and observes again, then sends an EventReconnected event.
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/henilmalaviya/gol/grid"
+)
+
+var (
+	// ErrClosed is returned by the requests made after Close.
+	ErrClosed = errors.New("client closed")
+	// ErrDisconnected is returned by the requests that were waiting for a
+	// reply when the connection dropped. They may or may not have run.
+	ErrDisconnected = errors.New("disconnected before the reply")
+)
+
+const (
+	DefaultReconnectDelay    = 500 * time.Millisecond
+	DefaultMaxReconnectDelay = 30 * time.Second
+	DefaultEventBuffer       = 256
+)
+
+// eventCodes are the codes of the messages the server pushes rather than
+// sends in reply.
+var eventCodes = map[string]bool{
+	codeObserveEvent: true,
+	"alert":          true,
+	"presence_event": true,
+	"cursor_event":   true,
+	"chat_event":     true,
+	"stats":          true,
+}
+
+// Options configure a Client. The zero value is usable.
+type Options struct {
+	// Header is sent with the WebSocket handshake.
+	Header http.Header
+
+	// Name, Color and Token are sent with the auth command on every
+	// connection when any of them is set.
+	Name  string
+	Color string
+	Token string
+
+	// ReconnectDelay is how long the client waits before reconnecting,
+	// doubling on each failed attempt up to MaxReconnectDelay. A negative
+	// delay disables reconnecting.
+	ReconnectDelay    time.Duration
+	MaxReconnectDelay time.Duration
+
+	// EventBuffer is the capacity of the Events channel. Events that do not
+	// fit are dropped and counted by Dropped.
+	EventBuffer int
+
+	// OnMessage receives the messages that are neither replies nor observe
+	// events, such as chat and stats. It runs on the read loop, so it must
+	// not block or make requests.
+	OnMessage func(Message)
+}
+
+// observation is what the client observes, to observe again after
+// reconnecting.
+type observation struct {
+	bounds       grid.Rectangle
+	detectPeriod bool
+}
+
+type Client struct {
+	url    string
+	opts   Options
+	dialer websocket.Dialer
+
+	conn      *websocket.Conn
+	pending   map[string]chan Message
+	observing *observation
+	closed    bool
+	mutex     sync.Mutex
+
+	writeMutex sync.Mutex
+
+	nextID    atomic.Int64
+	dropped   atomic.Int64
+	restoring sync.WaitGroup
+
+	events  chan Event
+	closing chan struct{}
+	done    chan struct{}
+}
+
+// Dial connects to the server's WebSocket endpoint at url, such as
+// ws://localhost:8080/game, and authenticates if the options ask for it.
+func Dial(ctx context.Context, url string, opts Options) (*Client, error) {
+	if opts.ReconnectDelay == 0 {
+		opts.ReconnectDelay = DefaultReconnectDelay
+	}
+	if opts.MaxReconnectDelay <= 0 {
+		opts.MaxReconnectDelay = DefaultMaxReconnectDelay
+	}
+	if opts.EventBuffer <= 0 {
+		opts.EventBuffer = DefaultEventBuffer
+	}
+
+	c := &Client{
+		url:     url,
+		opts:    opts,
+		dialer:  websocket.Dialer{HandshakeTimeout: 10 * time.Second, EnableCompression: true},
+		pending: make(map[string]chan Message),
+		events:  make(chan Event, opts.EventBuffer),
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	conn, _, err := c.dialer.DialContext(ctx, url, opts.Header)
+	if err != nil {
+		return nil, err
+	}
+	c.conn = conn
+	go c.run(conn)
+
+	if err := c.authenticate(ctx); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Events returns the channel observe events are delivered on. It is closed
+// once the client is closed.
+func (c *Client) Events() <-chan Event {
+	return c.events
+}
+
+// Dropped returns how many events did not fit in the Events channel.
+func (c *Client) Dropped() int64 {
+	return c.dropped.Load()
+}
+
+// Close closes the connection and stops reconnecting. Requests waiting for
+// a reply fail with ErrDisconnected.
+func (c *Client) Close() error {
+	c.mutex.Lock()
+	if c.closed {
+		c.mutex.Unlock()
+		return nil
+	}
+	c.closed = true
+	conn := c.conn
+	c.mutex.Unlock()
+
+	close(c.closing)
+	err := conn.Close()
+	<-c.done
+	return err
+}
+
+// Do sends a command and waits for its reply. A reply with the error code
+// is returned as a *ServerError, along with the message.
+func (c *Client) Do(ctx context.Context, command string, data any) (Message, error) {
+	id := "r" + strconv.FormatInt(c.nextID.Add(1), 10)
+	reply := make(chan Message, 1)
+
+	c.mutex.Lock()
+	if c.closed {
+		c.mutex.Unlock()
+		return Message{}, ErrClosed
+	}
+	conn := c.conn
+	c.pending[id] = reply
+	c.mutex.Unlock()
+
+	defer func() {
+		c.mutex.Lock()
+		delete(c.pending, id)
+		c.mutex.Unlock()
+	}()
+
+	c.writeMutex.Lock()
+	err := conn.WriteJSON(request{ID: id, Command: command, Data: data})
+	c.writeMutex.Unlock()
+	if err != nil {
+		return Message{}, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return Message{}, ctx.Err()
+	case msg, ok := <-reply:
+		if !ok {
+			return Message{}, ErrDisconnected
+		}
+		if msg.Code == codeError {
+			serverErr := &ServerError{}
+			if err := msg.Decode(serverErr); err != nil {
+				return msg, err
+			}
+			return msg, serverErr
+		}
+		return msg, nil
+	}
+}
+
+// run reads from the connection until the client is closed, reconnecting
+// whenever the connection drops.
+func (c *Client) run(conn *websocket.Conn) {
+	defer close(c.done)
+
+	for {
+		c.read(conn)
+		c.failPending()
+
+		if conn = c.reconnect(); conn == nil {
+			break
+		}
+		c.restoring.Add(1)
+		go c.restore(conn)
+	}
+
+	// restore may still emit an event.
+	c.restoring.Wait()
+	close(c.events)
+}
+
+// read dispatches the connection's messages until it fails.
+func (c *Client) read(conn *websocket.Conn) {
+	for {
+		_, frame, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		msg, err := ParseFrame(string(frame))
+		if err != nil {
+			continue
+		}
+		c.dispatch(msg)
+	}
+}
+
+func (c *Client) dispatch(msg Message) {
+	if !eventCodes[msg.Code] {
+		if id := msg.requestID(); id != "" {
+			c.mutex.Lock()
+			reply, ok := c.pending[id]
+			delete(c.pending, id)
+			c.mutex.Unlock()
+
+			if ok {
+				reply <- msg
+				return
+			}
+		}
+	}
+
+	if msg.Code == codeObserveEvent {
+		event, err := parseEvent(msg)
+		if err == nil {
+			c.emit(event)
+		}
+		return
+	}
+
+	if c.opts.OnMessage != nil {
+		c.opts.OnMessage(msg)
+	}
+}
+
+func (c *Client) emit(event Event) {
+	select {
+	case c.events <- event:
+	default:
+		c.dropped.Add(1)
+	}
+}
+
+// failPending fails the requests waiting for a reply.
+func (c *Client) failPending() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for id, reply := range c.pending {
+		close(reply)
+		delete(c.pending, id)
+	}
+}
+
+// reconnect dials until it succeeds, returning nil once the client is
+// closed or if reconnecting is disabled.
+func (c *Client) reconnect() *websocket.Conn {
+	if c.opts.ReconnectDelay < 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-c.closing:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	delay := c.opts.ReconnectDelay
+	for {
+		select {
+		case <-c.closing:
+			return nil
+		case <-time.After(delay):
+		}
+
+		conn, _, err := c.dialer.DialContext(ctx, c.url, c.opts.Header)
+		if err != nil {
+			delay = min(delay*2, c.opts.MaxReconnectDelay)
+			continue
+		}
+
+		c.mutex.Lock()
+		if c.closed {
+			c.mutex.Unlock()
+			conn.Close()
+			return nil
+		}
+		c.conn = conn
+		c.mutex.Unlock()
+		return conn
+	}
+}
+
+// restore authenticates and observes again on a new connection. If that
+// fails the connection is dropped, to be tried again.
+func (c *Client) restore(conn *websocket.Conn) {
+	defer c.restoring.Done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := c.authenticate(ctx); err != nil {
+		conn.Close()
+		return
+	}
+
+	c.mutex.Lock()
+	observing := c.observing
+	c.mutex.Unlock()
+
+	if observing != nil {
+		if err := c.observe(ctx, *observing); err != nil {
+			conn.Close()
+			return
+		}
+	}
+	c.emit(Event{Type: EventReconnected})
+}
@@ -0,0 +1,204 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/henilmalaviya/gol/grid"
+	"github.com/henilmalaviya/golw/game"
+	"github.com/henilmalaviya/golw/server"
+)
+
+// testServer serves the game's WebSocket protocol and keeps the server side
+// of its connections, so tests can drop them.
+type testServer struct {
+	*httptest.Server
+	gm *game.Manager
+
+	mutex sync.Mutex
+	conns []net.Conn
+}
+
+func newTestServer(t *testing.T) *testServer {
+	s := &testServer{gm: game.NewManager()}
+	s.Server = httptest.NewUnstartedServer(http.HandlerFunc(server.WebsocketHandler(s.gm)))
+	s.Listener = &recordingListener{Listener: s.Listener, server: s}
+	s.Start()
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *testServer) url() string {
+	return "ws" + strings.TrimPrefix(s.URL, "http")
+}
+
+// dropConnections closes the server side of every connection.
+func (s *testServer) dropConnections() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+	s.conns = nil
+}
+
+type recordingListener struct {
+	net.Listener
+	server *testServer
+}
+
+func (l *recordingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err == nil {
+		l.server.mutex.Lock()
+		l.server.conns = append(l.server.conns, conn)
+		l.server.mutex.Unlock()
+	}
+	return conn, err
+}
+
+func dial(t *testing.T, s *testServer, opts Options) *Client {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := Dial(ctx, s.url(), opts)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+// nextEvent returns the next event of the client.
+func nextEvent(t *testing.T, c *Client) Event {
+	t.Helper()
+
+	select {
+	case event, ok := <-c.Events():
+		if !ok {
+			t.Fatal("events channel closed")
+		}
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event received")
+		return Event{}
+	}
+}
+
+func TestDoConcurrent(t *testing.T) {
+	s := newTestServer(t)
+	c := dial(t, s, Options{})
+	ctx := context.Background()
+
+	// Each request changes a different number of cells, so a reply matched
+	// to the wrong request shows in its count.
+	const requests = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, requests)
+	for i := range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			cells := make([]grid.Cell, i+1)
+			for j := range cells {
+				cells[j] = grid.Cell{X: j, Y: i * 10}
+			}
+			result, err := c.SetCells(ctx, cells)
+			if err != nil {
+				errs <- err
+			} else if result.Changed != len(cells) {
+				errs <- fmt.Errorf("request %d: changed %d cells, want %d", i, result.Changed, len(cells))
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestDoServerError(t *testing.T) {
+	s := newTestServer(t)
+	c := dial(t, s, Options{})
+
+	msg, err := c.Do(context.Background(), "no_such_command", nil)
+
+	var serverErr *ServerError
+	if !errors.As(err, &serverErr) {
+		t.Fatalf("got error %v, want a *ServerError", err)
+	}
+	if serverErr.Message != "unknown command" {
+		t.Errorf("error message is %q, want %q", serverErr.Message, "unknown command")
+	}
+	if msg.Code != codeError {
+		t.Errorf("message code is %q, want %q", msg.Code, codeError)
+	}
+}
+
+func TestObserveEvents(t *testing.T) {
+	s := newTestServer(t)
+	c := dial(t, s, Options{Name: "tester"})
+	ctx := context.Background()
+
+	if err := c.Observe(ctx, *grid.NewRectangle(0, 0, 10, 10), false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.SetCells(ctx, []grid.Cell{{X: 5, Y: 5}}); err != nil {
+		t.Fatal(err)
+	}
+
+	event := nextEvent(t, c)
+	if event.Type != EventSetCell || event.Cell != (grid.Cell{X: 5, Y: 5}) {
+		t.Fatalf("got %+v, want the set cell at (5, 5)", event)
+	}
+	if event.Author == nil || event.Author.Name != "tester" {
+		t.Errorf("author is %+v, want tester", event.Author)
+	}
+
+	// The lone cell dies.
+	s.gm.Tick()
+
+	event = nextEvent(t, c)
+	if event.Type != EventTick || len(event.Born) != 0 || len(event.Died) != 1 || event.Died[0] != (grid.Cell{X: 5, Y: 5}) {
+		t.Fatalf("got %+v, want a tick killing (5, 5)", event)
+	}
+}
+
+func TestReconnect(t *testing.T) {
+	s := newTestServer(t)
+	c := dial(t, s, Options{ReconnectDelay: 10 * time.Millisecond})
+	ctx := context.Background()
+
+	if err := c.Observe(ctx, *grid.NewRectangle(0, 0, 10, 10), false); err != nil {
+		t.Fatal(err)
+	}
+
+	s.dropConnections()
+
+	if event := nextEvent(t, c); event.Type != EventReconnected {
+		t.Fatalf("got %+v, want %s", event, EventReconnected)
+	}
+
+	// The region is observed again on the new connection.
+	s.gm.EditCells([]grid.Cell{{X: 3, Y: 4}}, true, game.Author{})
+
+	event := nextEvent(t, c)
+	if event.Type != EventSetCell || event.Cell != (grid.Cell{X: 3, Y: 4}) {
+		t.Fatalf("got %+v, want the set cell at (3, 4)", event)
+	}
+
+	if _, err := c.SetCells(ctx, []grid.Cell{{X: 7, Y: 7}}); err != nil {
+		t.Fatalf("request after reconnecting: %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+
+	"github.com/henilmalaviya/gol/grid"
+)
+
+// authenticate sends the name, color and token of the options, if any.
+func (c *Client) authenticate(ctx context.Context) error {
+	data := map[string]any{}
+	if c.opts.Name != "" {
+		data["name"] = c.opts.Name
+	}
+	if c.opts.Color != "" {
+		data["color"] = c.opts.Color
+	}
+	if c.opts.Token != "" {
+		data["token"] = c.opts.Token
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	_, err := c.Do(ctx, "auth", data)
+	return err
+}
+
+// Sync returns every live cell along with the stats and topology of the
+// world.
+func (c *Client) Sync(ctx context.Context, bounds grid.Rectangle) (*SyncResult, error) {
+	msg, err := c.Do(ctx, "sync", map[string]any{"bounds": bounds.ToNestedArray()})
+	if err != nil {
+		return nil, err
+	}
+
+	var reply struct {
+		Cells    [][2]int `json:"cells"`
+		Stats    Stats    `json:"stats"`
+		Topology Topology `json:"topology"`
+	}
+	if err := msg.Decode(&reply); err != nil {
+		return nil, err
+	}
+	return &SyncResult{Cells: toCells(reply.Cells), Stats: reply.Stats, Topology: reply.Topology}, nil
+}
+
+// SetCells brings cells to life.
+func (c *Client) SetCells(ctx context.Context, cells []grid.Cell) (EditResult, error) {
+	return c.edit(ctx, "set_cells", cells)
+}
+
+// ClearCells kills cells.
+func (c *Client) ClearCells(ctx context.Context, cells []grid.Cell) (EditResult, error) {
+	return c.edit(ctx, "clear_cells", cells)
+}
+
+// ToggleCells flips cells between alive and dead.
+func (c *Client) ToggleCells(ctx context.Context, cells []grid.Cell) (EditResult, error) {
+	return c.edit(ctx, "toggle_cells", cells)
+}
+
+func (c *Client) edit(ctx context.Context, command string, cells []grid.Cell) (EditResult, error) {
+	msg, err := c.Do(ctx, command, map[string]any{"cells": toCoords(cells)})
+	if err != nil {
+		return EditResult{}, err
+	}
+
+	var result EditResult
+	err = msg.Decode(&result)
+	return result, err
+}
+
+// Observe starts sending the events of a region to Events, replacing the
+// region observed before. With detectPeriod the server also reports when
+// the region becomes periodic. The region is observed again after
+// reconnecting.
+func (c *Client) Observe(ctx context.Context, bounds grid.Rectangle, detectPeriod bool) error {
+	return c.observe(ctx, observation{bounds: bounds, detectPeriod: detectPeriod})
+}
+
+func (c *Client) observe(ctx context.Context, o observation) error {
+	data := map[string]any{"bounds": o.bounds.ToNestedArray()}
+	if o.detectPeriod {
+		data["detect_period"] = true
+	}
+	if _, err := c.Do(ctx, "observe", data); err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	c.observing = &o
+	c.mutex.Unlock()
+	return nil
+}
+
+// Unobserve stops the events of the observed region.
+func (c *Client) Unobserve(ctx context.Context) error {
+	if _, err := c.Do(ctx, "unobserve", nil); err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	c.observing = nil
+	c.mutex.Unlock()
+	return nil
+}
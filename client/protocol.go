@@ -0,0 +1,217 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/henilmalaviya/gol/grid"
+)
+
+// The server sends frames of the form "code;{json}\r\n", one per WebSocket
+// message, and reads commands as {"id", "command", "data"} JSON objects. The
+// reply to a command that had an id carries it in its data as request_id.
+
+const (
+	codeOk           = "ok"
+	codeError        = "error"
+	codeSyncOk       = "sync_ok"
+	codeObserveOk    = "observe_ok"
+	codeObserveEvent = "observe_event"
+)
+
+// Message is a frame received from the server.
+type Message struct {
+	Code string
+	Data json.RawMessage
+}
+
+// ParseFrame parses a "code;{json}" frame. The trailing "\r\n" is optional.
+func ParseFrame(frame string) (Message, error) {
+	code, data, ok := strings.Cut(strings.TrimRight(frame, "\r\n"), ";")
+	if !ok || code == "" {
+		return Message{}, fmt.Errorf("malformed frame %q", frame)
+	}
+	if !json.Valid([]byte(data)) {
+		return Message{}, fmt.Errorf("malformed data in %s frame", code)
+	}
+	return Message{Code: code, Data: json.RawMessage(data)}, nil
+}
+
+// Decode unmarshals the message's data into v.
+func (m Message) Decode(v any) error {
+	return json.Unmarshal(m.Data, v)
+}
+
+// requestID returns the id the message carries, if it is a reply.
+func (m Message) requestID() string {
+	var reply struct {
+		ID string `json:"request_id"`
+	}
+	json.Unmarshal(m.Data, &reply)
+	return reply.ID
+}
+
+// request is a command sent to the server.
+type request struct {
+	ID      string `json:"id"`
+	Command string `json:"command"`
+	Data    any    `json:"data,omitempty"`
+}
+
+// ServerError is an error message the server replied with.
+type ServerError struct {
+	Message string `json:"error"`
+}
+
+func (e *ServerError) Error() string {
+	return "server: " + e.Message
+}
+
+// Author identifies who made an edit.
+type Author struct {
+	ID    string `json:"id"`
+	Name  string `json:"name,omitempty"`
+	Color string `json:"color,omitempty"`
+}
+
+// Stats are the game stats the server reports.
+type Stats struct {
+	Generation     int        `json:"generation"`
+	BirthCount     int        `json:"birth_count"`
+	DeathCount     int        `json:"death_count"`
+	CulledCount    int        `json:"culled_count"`
+	Population     int        `json:"population"`
+	Bounds         *[2][2]int `json:"bounds"`
+	Period         int        `json:"period"`
+	StabilizedAt   int        `json:"stabilized_at"`
+	TickRate       float64    `json:"tick_rate"`
+	TickIntervalMs float64    `json:"tick_interval_ms"`
+	LastTickMs     float64    `json:"last_tick_ms"`
+	TickOverruns   int        `json:"tick_overruns"`
+	SkippedTicks   int        `json:"skipped_ticks"`
+}
+
+// Topology describes the shape of the world.
+type Topology struct {
+	Kind   string     `json:"kind"`
+	Bounds *[2][2]int `json:"bounds,omitempty"`
+}
+
+// SyncResult is the reply to Sync.
+type SyncResult struct {
+	Cells    []grid.Cell
+	Stats    Stats
+	Topology Topology
+}
+
+// EditResult is the reply to an edit: the generation it took effect at and
+// how many cells it changed.
+type EditResult struct {
+	Generation int `json:"generation"`
+	Changed    int `json:"changed"`
+}
+
+// EventType is the kind of an observe event.
+type EventType string
+
+const (
+	EventSetCell   EventType = "set_cell"
+	EventClearCell EventType = "clear_cell"
+	EventTick      EventType = "tick"
+	EventPeriodic  EventType = "periodic"
+	// EventReconnected is sent by the client, not the server, once it has
+	// reconnected and observes again. Events may have been missed in
+	// between, so the observed cells should be synced again.
+	EventReconnected EventType = "reconnected"
+)
+
+// Event is an observe event. Cell and Author are set for EventSetCell and
+// EventClearCell, Born and Died for EventTick, and Generation, Period,
+// StabilizedAt and Region for EventPeriodic.
+type Event struct {
+	Type EventType
+
+	Cell   grid.Cell
+	Author *Author
+
+	Born []grid.Cell
+	Died []grid.Cell
+
+	Generation   int
+	Period       int
+	StabilizedAt int
+	Region       *grid.Rectangle
+}
+
+func toCells(coords [][2]int) []grid.Cell {
+	cells := make([]grid.Cell, len(coords))
+	for i, c := range coords {
+		cells[i] = grid.Cell{X: c[0], Y: c[1]}
+	}
+	return cells
+}
+
+func toCoords(cells []grid.Cell) [][2]int {
+	coords := make([][2]int, len(cells))
+	for i, c := range cells {
+		coords[i] = [2]int{c.X, c.Y}
+	}
+	return coords
+}
+
+func toRectangle(nested [2][2]int) *grid.Rectangle {
+	return grid.NewRectangle(nested[0][0], nested[0][1], nested[1][0], nested[1][1])
+}
+
+// parseEvent decodes the data of an observe_event message.
+func parseEvent(msg Message) (Event, error) {
+	var frame struct {
+		Event EventType       `json:"event"`
+		Data  json.RawMessage `json:"data"`
+	}
+	if err := msg.Decode(&frame); err != nil {
+		return Event{}, err
+	}
+
+	event := Event{Type: frame.Event}
+	switch frame.Event {
+	case EventSetCell, EventClearCell:
+		var data struct {
+			Cell   [2]int  `json:"cell"`
+			Author *Author `json:"author"`
+		}
+		if err := json.Unmarshal(frame.Data, &data); err != nil {
+			return Event{}, err
+		}
+		event.Cell = grid.Cell{X: data.Cell[0], Y: data.Cell[1]}
+		event.Author = data.Author
+	case EventTick:
+		var data struct {
+			Born [][2]int `json:"bornCells"`
+			Died [][2]int `json:"diedCells"`
+		}
+		if err := json.Unmarshal(frame.Data, &data); err != nil {
+			return Event{}, err
+		}
+		event.Born = toCells(data.Born)
+		event.Died = toCells(data.Died)
+	case EventPeriodic:
+		var data struct {
+			Generation   int        `json:"generation"`
+			Period       int        `json:"period"`
+			StabilizedAt int        `json:"stabilized_at"`
+			Region       *[2][2]int `json:"region"`
+		}
+		if err := json.Unmarshal(frame.Data, &data); err != nil {
+			return Event{}, err
+		}
+		event.Generation = data.Generation
+		event.Period = data.Period
+		event.StabilizedAt = data.StabilizedAt
+		if data.Region != nil {
+			event.Region = toRectangle(*data.Region)
+		}
+	}
+	return event, nil
+}
@@ -0,0 +1,197 @@
+package env
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// The config file uses the environment variable names in lower case as
+// keys, for example tick_speed for TICK_SPEED. It is YAML, or TOML when its
+// name ends in .toml.
+
+// configKey returns the config file key of a field.
+func configKey(field reflect.StructField) string {
+	return strings.ToLower(field.Tag.Get("env"))
+}
+
+// readConfigFile returns the values of the config file by key.
+func readConfigFile(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]any)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		err = toml.Unmarshal(data, &values)
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		if err = decoder.Decode(&values); errors.Is(err, io.EOF) {
+			err = nil // empty file
+		}
+	default:
+		return nil, fmt.Errorf("config file %s must end in .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// setField parses a setting into a field.
+func setField(v reflect.Value, raw string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Int:
+		n, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("expected an integer, got %q", raw)
+		}
+		v.SetInt(int64(n))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("expected true or false, got %q", raw)
+		}
+		v.SetBool(b)
+	default:
+		panic("env: unsupported field type " + v.Kind().String())
+	}
+	return nil
+}
+
+// kindNames describes the field types in errors.
+var kindNames = map[reflect.Kind]string{
+	reflect.String: "a string",
+	reflect.Int:    "an integer",
+	reflect.Bool:   "true or false",
+}
+
+// setFieldFromConfig sets a field from a decoded config file value.
+func setFieldFromConfig(v reflect.Value, value any) error {
+	switch value := value.(type) {
+	case string:
+		if v.Kind() != reflect.String {
+			return fmt.Errorf("expected %s, got %q", kindNames[v.Kind()], value)
+		}
+		v.SetString(value)
+		return nil
+	case int, int64, uint64, float64, bool:
+		return setField(v, fmt.Sprint(value))
+	default:
+		return fmt.Errorf("expected %s, got %T", kindNames[v.Kind()], value)
+	}
+}
+
+// load reads the settings from their defaults, the config file and the
+// environment, in that order, and validates them. On error it still
+// returns usable settings, with defaults in place of the values that could
+// not be read.
+func load() (*Environment, error) {
+	var errs []error
+
+	e := &Environment{}
+	v := reflect.ValueOf(e).Elem()
+	t := v.Type()
+
+	for i := range t.NumField() {
+		if err := setField(v.Field(i), t.Field(i).Tag.Get("default")); err != nil {
+			panic("env: bad default for " + t.Field(i).Name)
+		}
+	}
+
+	if path := ConfigFile(); path != "" {
+		values, err := readConfigFile(path)
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		known := make([]string, 0, t.NumField())
+		for i := range t.NumField() {
+			key := configKey(t.Field(i))
+			known = append(known, key)
+
+			value, ok := values[key]
+			if !ok {
+				continue
+			}
+			if err := setFieldFromConfig(v.Field(i), value); err != nil {
+				errs = append(errs, fmt.Errorf("%s in %s: %w", key, path, err))
+			}
+		}
+
+		for key := range values {
+			if !slices.Contains(known, key) {
+				errs = append(errs, fmt.Errorf("unknown setting %s in %s", key, path))
+			}
+		}
+	}
+
+	for i := range t.NumField() {
+		key := t.Field(i).Tag.Get("env")
+		raw, ok := os.LookupEnv(key)
+		if !ok {
+			continue
+		}
+
+		field := v.Field(i)
+		previous := field.Interface()
+		if err := setField(field, raw); err != nil {
+			field.Set(reflect.ValueOf(previous))
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+		}
+	}
+
+	if err := e.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	return e, errors.Join(errs...)
+}
+
+// Reload reads the settings again and applies the ones that may change at
+// runtime. It returns the names of the settings that changed, and of those
+// that changed but need a restart to take effect. If any setting is
+// invalid, nothing is applied.
+func Reload() (changed, needRestart []string, err error) {
+	next, err := load()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	current := Get()
+	updated := *current
+
+	cv, nv, uv := reflect.ValueOf(current).Elem(), reflect.ValueOf(next).Elem(), reflect.ValueOf(&updated).Elem()
+	t := cv.Type()
+	for i := range t.NumField() {
+		if cv.Field(i).Equal(nv.Field(i)) {
+			continue
+		}
+
+		name := t.Field(i).Tag.Get("env")
+		if t.Field(i).Tag.Get("reload") != "true" {
+			needRestart = append(needRestart, name)
+			continue
+		}
+		uv.Field(i).Set(nv.Field(i))
+		changed = append(changed, name)
+	}
+
+	if len(changed) > 0 {
+		env.Store(&updated)
+	}
+	return changed, needRestart, nil
+}
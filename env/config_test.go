@@ -0,0 +1,163 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// useConfigFile writes a config file with the given name and contents and
+// points CONFIG_FILE at it.
+func useConfigFile(t *testing.T, name, contents string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIG_FILE", path)
+}
+
+// unsetEnv removes an environment variable for the rest of the test.
+func unsetEnv(t *testing.T, key string) {
+	t.Setenv(key, "")
+	os.Unsetenv(key)
+}
+
+// useSettings makes e the current settings for the rest of the test.
+func useSettings(t *testing.T, e *Environment) {
+	previous := Get()
+	env.Store(e)
+	t.Cleanup(func() { env.Store(previous) })
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	useConfigFile(t, "golw.yaml", "tick_speed: 100\nport: \"9000\"\nlog_level: debug\n")
+	t.Setenv("TICK_SPEED", "300")
+	unsetEnv(t, "PORT")
+	unsetEnv(t, "LOG_LEVEL")
+	unsetEnv(t, "SAVE_DIR")
+
+	e, err := load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		got  any
+		want any
+	}{
+		{name: "environment over config file", got: e.TickSpeed, want: 300},
+		{name: "config file over default", got: e.Port, want: "9000"},
+		{name: "config file string", got: e.LogLevel, want: "debug"},
+		{name: "default", got: e.SaveDirectory, want: "./saves"},
+	}
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestLoadTOML(t *testing.T) {
+	useConfigFile(t, "golw.toml", "tick_workers = 3\nweb_viewer = false\n")
+	unsetEnv(t, "TICK_WORKERS")
+	unsetEnv(t, "WEB_VIEWER")
+
+	e, err := load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.TickWorkers != 3 || e.WebViewer {
+		t.Errorf("got tick_workers %d and web_viewer %t, want 3 and false", e.TickWorkers, e.WebViewer)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		contents string
+		env      map[string]string
+		want     string
+		// tickSpeed is what TICK_SPEED is left at. Values that cannot be
+		// read keep the default; values out of range are kept.
+		tickSpeed int
+	}{
+		{name: "unknown setting", file: "golw.yaml", contents: "tick_sped: 100\n", want: "unknown setting tick_sped", tickSpeed: 250},
+		{name: "wrong type", file: "golw.yaml", contents: "tick_speed: fast\n", want: "tick_speed in", tickSpeed: 250},
+		{name: "unknown extension", file: "golw.json", contents: "{}", want: "must end in", tickSpeed: 250},
+		{name: "invalid value", file: "golw.yaml", contents: "tick_speed: -1\n", want: "TICK_SPEED must be positive", tickSpeed: -1},
+		{name: "invalid environment", file: "golw.yaml", env: map[string]string{"TICK_SPEED": "fast"}, want: "TICK_SPEED: expected an integer", tickSpeed: 250},
+		{name: "invalid grpc port", file: "golw.yaml", contents: "grpc_port: \"70000\"\n", want: "GRPC_PORT must be a port number", tickSpeed: 250},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useConfigFile(t, tt.file, tt.contents)
+			unsetEnv(t, "TICK_SPEED")
+			unsetEnv(t, "GRPC_PORT")
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			e, err := load()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want one containing %q", err, tt.want)
+			}
+			if e.TickSpeed != tt.tickSpeed {
+				t.Errorf("tick speed is %d, want %d", e.TickSpeed, tt.tickSpeed)
+			}
+		})
+	}
+}
+
+func TestReload(t *testing.T) {
+	unsetEnv(t, "TICK_SPEED")
+	unsetEnv(t, "TICK_WORKERS")
+	useConfigFile(t, "golw.yaml", "tick_speed: 100\n")
+	current, err := load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	useSettings(t, current)
+
+	useConfigFile(t, "golw.yaml", "tick_speed: 50\ntick_workers: 4\n")
+	changed, needRestart, err := Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(changed, []string{"TICK_SPEED"}) {
+		t.Errorf("changed is %v, want [TICK_SPEED]", changed)
+	}
+	if !reflect.DeepEqual(needRestart, []string{"TICK_WORKERS"}) {
+		t.Errorf("need restart is %v, want [TICK_WORKERS]", needRestart)
+	}
+	if Get().TickSpeed != 50 || Get().TickWorkers != current.TickWorkers {
+		t.Errorf("got tick speed %d and workers %d, want 50 and %d", Get().TickSpeed, Get().TickWorkers, current.TickWorkers)
+	}
+}
+
+func TestReloadInvalid(t *testing.T) {
+	unsetEnv(t, "TICK_SPEED")
+	unsetEnv(t, "MAX_CHAT_LENGTH")
+	useConfigFile(t, "golw.yaml", "tick_speed: 100\n")
+	current, err := load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	useSettings(t, current)
+
+	// The valid change is not applied either.
+	useConfigFile(t, "golw.yaml", "tick_speed: 50\nmax_chat_length: 0\n")
+	if _, _, err := Reload(); err == nil {
+		t.Fatal("got no error for an invalid setting")
+	}
+	if Get() != current {
+		t.Errorf("settings were replaced: tick speed %d", Get().TickSpeed)
+	}
+}
@@ -1,71 +1,95 @@
 package env
 
 import (
-	"fmt"
 	"os"
-	"strconv"
+	"reflect"
+	"sync/atomic"
 
 	"github.com/joho/godotenv"
 )
 
+// Environment holds the server settings. Each field is read from the
+// environment variable in its env tag, falling back to the config file
+// named by CONFIG_FILE and then to its default. Fields tagged reload can be
+// changed at runtime with Reload.
 type Environment struct {
-	Port                 string
-	TickSpeed            int
-	WSEndpoint           string
-	WebSocketOriginCheck bool
-	MaxObserveRegionSize int
-	LogLevel             string
-	SaveInterval         int
-	SaveDirectory        string
-	MaxSavesFiles        int
+	Port                 string `env:"PORT" default:"8080"`
+	GRPCPort             string `env:"GRPC_PORT" default:""`
+	TickSpeed            int    `env:"TICK_SPEED" default:"250" reload:"true"`
+	TickWorkers          int    `env:"TICK_WORKERS" default:"0"`
+	TickOverrunPolicy    string `env:"TICK_OVERRUN_POLICY" default:"skip"`
+	TickMaxCatchUp       int    `env:"TICK_MAX_CATCH_UP" default:"10"`
+	WSEndpoint           string `env:"WS_ENDPOINT" default:"/game"`
+	WebSocketOriginCheck bool   `env:"WS_ORIGIN_CHECK" default:"false"`
+	HTTPTransport        bool   `env:"HTTP_TRANSPORT" default:"true"`
+	WebViewer            bool   `env:"WEB_VIEWER" default:"true"`
+	MaxObserveRegionSize int    `env:"MAX_OBSERVE_REGION_SIZE" default:"1000" reload:"true"`
+	WorldTopology        string `env:"WORLD_TOPOLOGY" default:"infinite"`
+	WorldBounds          string `env:"WORLD_BOUNDS" default:"-500,-500,499,499"`
+	PopulationSoftCap    int    `env:"POPULATION_SOFT_CAP" default:"0"`
+	PopulationHardCap    int    `env:"POPULATION_HARD_CAP" default:"0"`
+	PopulationCapAction  string `env:"POPULATION_CAP_ACTION" default:"pause"`
+	PopulationCullBounds string `env:"POPULATION_CULL_BOUNDS" default:"-500,-500,499,499"`
+	AdminToken           string `env:"ADMIN_TOKEN" default:""`
+	UserTokens           string `env:"USER_TOKENS" default:"" reload:"true"`
+	CursorRateLimit      int    `env:"CURSOR_RATE_LIMIT" default:"20" reload:"true"`
+	ChatRateLimit        int    `env:"CHAT_RATE_LIMIT" default:"20" reload:"true"`
+	MaxChatLength        int    `env:"MAX_CHAT_LENGTH" default:"500" reload:"true"`
+	UndoHistory          int    `env:"UNDO_HISTORY" default:"50"`
+	UndoMaxGenerations   int    `env:"UNDO_MAX_GENERATIONS" default:"100"`
+	MaxScheduledEdits    int    `env:"MAX_SCHEDULED_EDITS" default:"100" reload:"true"`
+	PeriodWindow         int    `env:"PERIOD_DETECTION_WINDOW" default:"64"`
+	CensusInterval       int    `env:"CENSUS_INTERVAL" default:"60"`
+	StatsHistorySize     int    `env:"STATS_HISTORY_SIZE" default:"3600"`
+	LogLevel             string `env:"LOG_LEVEL" default:"info" reload:"true"`
+	SaveInterval         int    `env:"SAVE_INTERVAL" default:"60"`
+	SaveDirectory        string `env:"SAVE_DIR" default:"./saves"`
+	MaxSavesFiles        int    `env:"MAX_SAVE_FILES" default:"10"`
 }
 
-var env *Environment
+var env atomic.Pointer[Environment]
 
-func getEnvString(key, fallback string) string {
-	if value, exists := os.LookupEnv(key); exists {
-		return value
-	}
-	return fallback
+// loadErr is the error of the initial load, reported by LoadError.
+var loadErr error
+
+func Get() *Environment {
+	return env.Load()
 }
 
-func getEnvInt(key string, fallback int) int {
-	valueStr := getEnvString(key, fmt.Sprintf("%d", fallback))
-	intValue, err := strconv.Atoi(valueStr)
-	if err != nil {
-		return fallback
-	}
-	return intValue
+// LoadError returns what was wrong with the settings at startup. The
+// settings in effect use the defaults for the invalid values.
+func LoadError() error {
+	return loadErr
 }
 
-func getEnvBool(key string, fallback bool) bool {
-	valueStr := getEnvString(key, fmt.Sprintf("%t", fallback))
-	boolValue, err := strconv.ParseBool(valueStr)
-	if err != nil {
-		return fallback
+// Keys returns the names of the settings, in the order of Environment.
+func Keys() []string {
+	t := reflect.TypeFor[Environment]()
+	keys := make([]string, t.NumField())
+	for i := range t.NumField() {
+		keys[i] = t.Field(i).Tag.Get("env")
 	}
-	return boolValue
+	return keys
 }
 
-func Get() *Environment {
-	return env
+// Load reads every setting again, for when the environment changed after
+// startup, for example from command-line flags. Unlike Reload it also
+// replaces the settings that need a restart, and stores the settings even
+// when some are invalid, as the initial load does.
+func Load() error {
+	e, err := load()
+	env.Store(e)
+	loadErr = err
+	return err
+}
+
+// ConfigFile returns the path of the config file, or "" if none is used.
+func ConfigFile() string {
+	return os.Getenv("CONFIG_FILE")
 }
 
 func init() {
 	godotenv.Load()
-	// if err != nil {
-	// 	panic("Error loading .env file")
-	// }
 
-	env = &Environment{
-		Port:                 getEnvString("PORT", "8080"),
-		TickSpeed:            getEnvInt("TICK_SPEED", 250),
-		WSEndpoint:           getEnvString("WS_ENDPOINT", "/game"),
-		WebSocketOriginCheck: getEnvBool("WS_ORIGIN_CHECK", false),
-		MaxObserveRegionSize: getEnvInt("MAX_OBSERVE_REGION_SIZE", 1000),
-		LogLevel:             getEnvString("LOG_LEVEL", "info"),
-		SaveInterval:         getEnvInt("SAVE_INTERVAL", 60),
-		SaveDirectory:        getEnvString("SAVE_DIR", "./saves"),
-		MaxSavesFiles:        getEnvInt("MAX_SAVE_FILES", 10),
-	}
+	Load()
 }
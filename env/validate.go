@@ -0,0 +1,84 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	tickOverrunPolicies  = []string{"skip", "catchup", "slow"}
+	worldTopologies      = []string{"infinite", "bounded", "torus", "klein"}
+	populationCapActions = []string{"pause", "reject", "cull"}
+	logLevels            = []string{"trace", "debug", "info", "warn", "warning", "error", "fatal"}
+)
+
+// Validate checks that every setting is in range. It returns all problems
+// at once.
+func (e *Environment) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	port, err := strconv.Atoi(e.Port)
+	check(err == nil && port > 0 && port < 65536, "PORT must be a port number, got %q", e.Port)
+	if e.GRPCPort != "" {
+		port, err := strconv.Atoi(e.GRPCPort)
+		check(err == nil && port > 0 && port < 65536, "GRPC_PORT must be a port number, got %q", e.GRPCPort)
+	}
+	check(e.TickSpeed > 0, "TICK_SPEED must be positive, got %d", e.TickSpeed)
+	check(e.TickWorkers >= 0, "TICK_WORKERS must not be negative, got %d", e.TickWorkers)
+	check(slices.Contains(tickOverrunPolicies, e.TickOverrunPolicy), "TICK_OVERRUN_POLICY must be one of %s, got %q", strings.Join(tickOverrunPolicies, ", "), e.TickOverrunPolicy)
+	check(e.TickMaxCatchUp >= 0, "TICK_MAX_CATCH_UP must not be negative, got %d", e.TickMaxCatchUp)
+	check(strings.HasPrefix(e.WSEndpoint, "/"), "WS_ENDPOINT must start with /, got %q", e.WSEndpoint)
+	check(e.MaxObserveRegionSize > 0, "MAX_OBSERVE_REGION_SIZE must be positive, got %d", e.MaxObserveRegionSize)
+	check(slices.Contains(worldTopologies, e.WorldTopology), "WORLD_TOPOLOGY must be one of %s, got %q", strings.Join(worldTopologies, ", "), e.WorldTopology)
+	check(e.PopulationSoftCap >= 0, "POPULATION_SOFT_CAP must not be negative, got %d", e.PopulationSoftCap)
+	check(e.PopulationHardCap >= 0, "POPULATION_HARD_CAP must not be negative, got %d", e.PopulationHardCap)
+	check(e.PopulationHardCap == 0 || e.PopulationSoftCap <= e.PopulationHardCap, "POPULATION_SOFT_CAP must not exceed POPULATION_HARD_CAP")
+	check(slices.Contains(populationCapActions, e.PopulationCapAction), "POPULATION_CAP_ACTION must be one of %s, got %q", strings.Join(populationCapActions, ", "), e.PopulationCapAction)
+	_, err = ParseUserTokens(e.UserTokens)
+	check(err == nil, "USER_TOKENS %v", err)
+	check(e.CursorRateLimit >= 0, "CURSOR_RATE_LIMIT must not be negative, got %d", e.CursorRateLimit)
+	check(e.ChatRateLimit >= 0, "CHAT_RATE_LIMIT must not be negative, got %d", e.ChatRateLimit)
+	check(e.MaxChatLength > 0, "MAX_CHAT_LENGTH must be positive, got %d", e.MaxChatLength)
+	check(e.UndoHistory >= 0, "UNDO_HISTORY must not be negative, got %d", e.UndoHistory)
+	check(e.UndoMaxGenerations >= 0, "UNDO_MAX_GENERATIONS must not be negative, got %d", e.UndoMaxGenerations)
+	check(e.MaxScheduledEdits >= 0, "MAX_SCHEDULED_EDITS must not be negative, got %d", e.MaxScheduledEdits)
+	check(e.PeriodWindow >= 0, "PERIOD_DETECTION_WINDOW must not be negative, got %d", e.PeriodWindow)
+	check(e.CensusInterval >= 0, "CENSUS_INTERVAL must not be negative, got %d", e.CensusInterval)
+	check(e.StatsHistorySize >= 0, "STATS_HISTORY_SIZE must not be negative, got %d", e.StatsHistorySize)
+	check(slices.Contains(logLevels, strings.ToLower(e.LogLevel)), "LOG_LEVEL must be one of %s, got %q", strings.Join(logLevels, ", "), e.LogLevel)
+	check(e.SaveInterval >= 0, "SAVE_INTERVAL must not be negative, got %d", e.SaveInterval)
+	check(e.SaveDirectory != "", "SAVE_DIR must not be empty")
+	check(e.MaxSavesFiles >= 0, "MAX_SAVE_FILES must not be negative, got %d", e.MaxSavesFiles)
+
+	return errors.Join(errs...)
+}
+
+// ParseUserTokens parses user tokens written as "name=token,name=token"
+// into a map from name to token.
+func ParseUserTokens(value string) (map[string]string, error) {
+	tokens := make(map[string]string)
+	if strings.TrimSpace(value) == "" {
+		return tokens, nil
+	}
+
+	for _, entry := range strings.Split(value, ",") {
+		name, token, ok := strings.Cut(entry, "=")
+		name, token = strings.TrimSpace(name), strings.TrimSpace(token)
+		if !ok || name == "" || token == "" {
+			return nil, fmt.Errorf("entry %q must have the form name=token", strings.TrimSpace(entry))
+		}
+		if _, ok := tokens[name]; ok {
+			return nil, fmt.Errorf("user %q is listed more than once", name)
+		}
+		tokens[name] = token
+	}
+	return tokens, nil
+}
@@ -0,0 +1,316 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/henilmalaviya/gol/grid"
+	"github.com/henilmalaviya/gol/patterns"
+	"github.com/henilmalaviya/golw/util"
+)
+
+// The census splits the live cells into 8-connected components, brings each
+// one to a canonical form under rotation and reflection and looks it up in a
+// library of known objects. Components that are not in the library are run
+// on their own to tell still lifes, oscillators and spaceships apart, and are
+// named after apgsearch's prefixes: xs<population>, xp<period> and
+// xq<period>. Components that do not settle are merged with unsettled ones
+// up to two cells away and classified again, since some phases of objects
+// such as the spaceships are not connected.
+
+const (
+	// censusMaxPeriod is how long an unknown component is run for.
+	censusMaxPeriod = 30
+	// censusMaxSize is the largest component that is run at all. Larger
+	// ones are counted as "other".
+	censusMaxSize = 500
+)
+
+// Census counts the objects in the world at a generation.
+type Census struct {
+	Generation int            `json:"generation"`
+	Population int            `json:"population"`
+	Objects    int            `json:"objects"`
+	Counts     map[string]int `json:"counts"`
+	TakenAt    time.Time      `json:"taken_at"`
+}
+
+// KnownObject is an entry of the census library.
+type KnownObject struct {
+	Name  string
+	Cells []grid.Cell
+}
+
+// picture parses rows of '.' and 'O' separated by '/'.
+func picture(rows string) []grid.Cell {
+	var cells []grid.Cell
+	for y, row := range strings.Split(rows, "/") {
+		for x, ch := range row {
+			if ch == 'O' {
+				cells = append(cells, grid.Cell{X: x, Y: y})
+			}
+		}
+	}
+	return cells
+}
+
+// CensusLibrary lists the objects the census recognizes, in one phase each.
+var CensusLibrary = []KnownObject{
+	{Name: "block", Cells: picture("OO/OO")},
+	{Name: "beehive", Cells: picture(".OO./O..O/.OO.")},
+	{Name: "loaf", Cells: picture(".OO./O..O/.O.O/..O.")},
+	{Name: "boat", Cells: picture("OO./O.O/.O.")},
+	{Name: "ship", Cells: picture("OO./O.O/.OO")},
+	{Name: "tub", Cells: picture(".O./O.O/.O.")},
+	{Name: "pond", Cells: picture(".OO./O..O/O..O/.OO.")},
+	{Name: "blinker", Cells: patterns.Blinker().GetCells()},
+	{Name: "toad", Cells: picture(".OOO/OOO.")},
+	{Name: "beacon", Cells: picture("OO../OO../..OO/..OO")},
+	{Name: "glider", Cells: patterns.Glider().GetCells()},
+	{Name: "lwss", Cells: picture(".O..O/O..../O...O/OOOO.")},
+	{Name: "mwss", Cells: picture("...O../.O...O/O...../O....O/OOOOO.")},
+	{Name: "hwss", Cells: picture("...OO../.O....O/O....../O.....O/OOOOOO.")},
+}
+
+// censusNames maps the canonical form of every phase of the library's
+// objects to their name.
+var censusNames = func() map[string]string {
+	names := make(map[string]string)
+	for _, obj := range CensusLibrary {
+		cells := obj.Cells
+		for range censusMaxPeriod {
+			key := canonicalForm(cells)
+			if _, ok := names[key]; ok {
+				break
+			}
+			names[key] = obj.Name
+			cells = step(cells)
+		}
+	}
+	return names
+}()
+
+// step returns the next generation of cells on an empty, infinite grid.
+func step(cells []grid.Cell) []grid.Cell {
+	born, died := ComputeNextGeneration(cells, 1)
+
+	dead := make(map[grid.Cell]struct{}, len(died))
+	for _, c := range died {
+		dead[c] = struct{}{}
+	}
+
+	next := make([]grid.Cell, 0, len(cells)-len(died)+len(born))
+	for _, c := range cells {
+		if _, ok := dead[c]; !ok {
+			next = append(next, c)
+		}
+	}
+	return append(next, born...)
+}
+
+// normalize moves cells so their bounding box starts at the origin, sorts
+// them and returns the offset that was removed.
+func normalize(cells []grid.Cell) ([]grid.Cell, grid.Cell) {
+	if len(cells) == 0 {
+		return nil, grid.Cell{}
+	}
+
+	minX, minY := cells[0].X, cells[0].Y
+	for _, c := range cells[1:] {
+		minX, minY = min(minX, c.X), min(minY, c.Y)
+	}
+
+	out := make([]grid.Cell, len(cells))
+	for i, c := range cells {
+		out[i] = grid.Cell{X: c.X - minX, Y: c.Y - minY}
+	}
+	slices.SortFunc(out, func(a, b grid.Cell) int {
+		if a.Y != b.Y {
+			return a.Y - b.Y
+		}
+		return a.X - b.X
+	})
+	return out, grid.Cell{X: minX, Y: minY}
+}
+
+func encodeCells(cells []grid.Cell) string {
+	var b strings.Builder
+	for _, c := range cells {
+		b.WriteString(strconv.Itoa(c.X))
+		b.WriteByte(',')
+		b.WriteString(strconv.Itoa(c.Y))
+		b.WriteByte(';')
+	}
+	return b.String()
+}
+
+// canonicalForm encodes cells independently of their position and
+// orientation: the smallest encoding among the eight symmetries.
+func canonicalForm(cells []grid.Cell) string {
+	var best string
+	transformed := make([]grid.Cell, len(cells))
+	for i := range 8 {
+		for j, c := range cells {
+			x, y := c.X, c.Y
+			if i&4 != 0 {
+				x = -x
+			}
+			for range i & 3 {
+				x, y = -y, x
+			}
+			transformed[j] = grid.Cell{X: x, Y: y}
+		}
+
+		norm, _ := normalize(transformed)
+		if key := encodeCells(norm); i == 0 || key < best {
+			best = key
+		}
+	}
+	return best
+}
+
+// components splits cells into groups whose cells are at most radius apart,
+// 8-connected components for a radius of one.
+func components(cells []grid.Cell, radius int) [][]grid.Cell {
+	unvisited := make(map[grid.Cell]struct{}, len(cells))
+	for _, c := range cells {
+		unvisited[c] = struct{}{}
+	}
+
+	var result [][]grid.Cell
+	for _, start := range cells {
+		if _, ok := unvisited[start]; !ok {
+			continue
+		}
+		delete(unvisited, start)
+
+		component := []grid.Cell{start}
+		for i := 0; i < len(component); i++ {
+			c := component[i]
+			for dy := -radius; dy <= radius; dy++ {
+				for dx := -radius; dx <= radius; dx++ {
+					n := grid.Cell{X: c.X + dx, Y: c.Y + dy}
+					if _, ok := unvisited[n]; ok {
+						delete(unvisited, n)
+						component = append(component, n)
+					}
+				}
+			}
+		}
+		result = append(result, component)
+	}
+	return result
+}
+
+// classify names a component, running it on its own when it is not in the
+// library.
+func classify(cells []grid.Cell) string {
+	if name, ok := censusNames[canonicalForm(cells)]; ok {
+		return name
+	}
+	if len(cells) > censusMaxSize {
+		return "other"
+	}
+
+	start, origin := normalize(cells)
+	startKey := encodeCells(start)
+
+	current := cells
+	for period := 1; period <= censusMaxPeriod; period++ {
+		current = step(current)
+		norm, offset := normalize(current)
+		if encodeCells(norm) != startKey {
+			continue
+		}
+
+		switch {
+		case offset != origin:
+			return fmt.Sprintf("xq%d", period)
+		case period == 1:
+			return fmt.Sprintf("xs%d", len(cells))
+		default:
+			return fmt.Sprintf("xp%d", period)
+		}
+	}
+	return "other"
+}
+
+// TakeCensus counts the objects among cells.
+func TakeCensus(cells []grid.Cell) map[string]int {
+	counts := make(map[string]int)
+
+	var unsettled []grid.Cell
+	for _, component := range components(cells, 1) {
+		if name := classify(component); name != "other" {
+			counts[name]++
+		} else {
+			unsettled = append(unsettled, component...)
+		}
+	}
+
+	for _, component := range components(unsettled, 2) {
+		counts[classify(component)]++
+	}
+	return counts
+}
+
+// RunCensus takes a census of the current generation and keeps it as the
+// latest one.
+func (m *Manager) RunCensus() Census {
+	m.mutex.Lock()
+	generation := m.stats.Generation
+	cells := m.game.GetGrid().GetCells()
+	m.mutex.Unlock()
+
+	counts := TakeCensus(cells)
+	objects := 0
+	for _, n := range counts {
+		objects += n
+	}
+
+	census := Census{
+		Generation: generation,
+		Population: len(cells),
+		Objects:    objects,
+		Counts:     counts,
+		TakenAt:    time.Now(),
+	}
+
+	m.censusMutex.Lock()
+	m.census = &census
+	m.censusMutex.Unlock()
+
+	return census
+}
+
+// LastCensus returns the latest census, or nil if none was taken yet.
+func (m *Manager) LastCensus() *Census {
+	m.censusMutex.Lock()
+	defer m.censusMutex.Unlock()
+	return m.census
+}
+
+// StartCensus takes a census every interval until StopCensus is called.
+func (m *Manager) StartCensus(interval time.Duration) {
+	if interval <= 0 || m.censusTicker != nil {
+		return
+	}
+
+	m.censusTicker = time.NewTicker(interval)
+	go func(ticker *time.Ticker) {
+		for range ticker.C {
+			census := m.RunCensus()
+			util.GetLogger().Debug("Census taken", "generation", census.Generation, "objects", census.Objects)
+		}
+	}(m.censusTicker)
+}
+
+func (m *Manager) StopCensus() {
+	if m.censusTicker != nil {
+		m.censusTicker.Stop()
+		m.censusTicker = nil
+	}
+}
@@ -0,0 +1,71 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/henilmalaviya/gol/grid"
+)
+
+func TestClassifyKnownObjects(t *testing.T) {
+	for _, obj := range CensusLibrary {
+		cells := obj.Cells
+		for phase := range 4 {
+			for _, flip := range []Flip{FlipNone, FlipHorizontal} {
+				for rotate := 0; rotate < 360; rotate += 90 {
+					moved, _, err := Transform(cells, -7, 13, rotate, flip)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if got := classify(moved); got != obj.Name {
+						t.Errorf("%s in phase %d, flipped %q and rotated %d: got %s", obj.Name, phase, flip, rotate, got)
+					}
+				}
+			}
+			cells = step(cells)
+		}
+	}
+}
+
+func TestClassifyUnknownObjects(t *testing.T) {
+	tests := []struct {
+		name  string
+		cells []grid.Cell
+		want  string
+	}{
+		{name: "barge", cells: picture(".O../O.O./.O.O/..O."), want: "xs6"},
+		{name: "clock", cells: picture("..O./O.O./.O.O/.O.."), want: "xp2"},
+		{name: "r-pentomino", cells: picture(".OO/OO./.O."), want: "other"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classify(tt.cells); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTakeCensus(t *testing.T) {
+	tests := []struct {
+		name  string
+		cells []grid.Cell
+		want  map[string]int
+	}{
+		{name: "empty", cells: nil, want: map[string]int{}},
+		{name: "objects apart", cells: picture("OO.OOO/OO...."), want: map[string]int{"block": 1, "blinker": 1}},
+		{name: "blocks touching diagonally", cells: picture("OO../OO../..OO/..OO"), want: map[string]int{"beacon": 1}},
+		{name: "objects touching and reacting", cells: picture("OO..../OO..../..OO../..O.O./...O.."), want: map[string]int{"other": 1}},
+		// This phase of the mwss falls apart into three pieces.
+		{name: "disconnected phase", cells: picture("...O../.O...O/O...../O....O/OOOOO."), want: map[string]int{"mwss": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TakeCensus(tt.cells); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
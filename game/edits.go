@@ -0,0 +1,272 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/henilmalaviya/gol/grid"
+)
+
+// CellEdit sets a cell to a given state.
+type CellEdit struct {
+	Cell  grid.Cell
+	Alive bool
+}
+
+// StampMode decides how a stamped pattern combines with the cells under it.
+type StampMode string
+
+const (
+	// StampModeOr sets the pattern's cells and leaves the rest alone.
+	StampModeOr StampMode = "or"
+	// StampModeXor toggles the pattern's cells.
+	StampModeXor StampMode = "xor"
+	// StampModeAnd keeps only the live cells under the pattern's bounding
+	// box that are also set in the pattern.
+	StampModeAnd StampMode = "and"
+	// StampModeReplace makes the pattern's bounding box an exact copy of
+	// the pattern.
+	StampModeReplace StampMode = "replace"
+)
+
+func ParseStampMode(mode string) (StampMode, error) {
+	switch StampMode(mode) {
+	case StampModeOr, StampModeXor, StampModeAnd, StampModeReplace:
+		return StampMode(mode), nil
+	default:
+		return "", fmt.Errorf("unknown stamp mode %q", mode)
+	}
+}
+
+type Flip string
+
+const (
+	FlipNone       Flip = ""
+	FlipHorizontal Flip = "horizontal"
+	FlipVertical   Flip = "vertical"
+)
+
+func ParseFlip(flip string) (Flip, error) {
+	switch Flip(flip) {
+	case FlipNone, FlipHorizontal, FlipVertical:
+		return Flip(flip), nil
+	default:
+		return "", fmt.Errorf("unknown flip %q", flip)
+	}
+}
+
+// Transform flips then rotates a pattern clockwise by rotate degrees (a
+// multiple of 90) and moves it so the top-left corner of its bounding box is
+// at (atX, atY). It returns the moved cells and their bounding box.
+func Transform(cells []grid.Cell, atX, atY, rotate int, flip Flip) ([]grid.Cell, grid.Rectangle, error) {
+	if rotate%90 != 0 {
+		return nil, grid.Rectangle{}, fmt.Errorf("rotation must be a multiple of 90 degrees, got %d", rotate)
+	}
+	if len(cells) == 0 {
+		return nil, grid.Rectangle{}, nil
+	}
+	turns := ((rotate/90)%4 + 4) % 4
+
+	out := make([]grid.Cell, len(cells))
+	for i, c := range cells {
+		x, y := c.X, c.Y
+		switch flip {
+		case FlipHorizontal:
+			x = -x
+		case FlipVertical:
+			y = -y
+		}
+		for range turns {
+			x, y = -y, x
+		}
+		out[i] = grid.Cell{X: x, Y: y}
+	}
+
+	minX, minY := out[0].X, out[0].Y
+	maxX, maxY := minX, minY
+	for _, c := range out {
+		minX, maxX = min(minX, c.X), max(maxX, c.X)
+		minY, maxY = min(minY, c.Y), max(maxY, c.Y)
+	}
+
+	for i := range out {
+		out[i].X += atX - minX
+		out[i].Y += atY - minY
+	}
+
+	bounds := *grid.NewRectangle(atX, atY, atX+maxX-minX, atY+maxY-minY)
+	return out, bounds, nil
+}
+
+// applyEditsLocked applies the edits, notifies observers of each changed
+// cell and records the changes in the author's journal. Cells outside the
+// world are ignored. Edits touching a zone the author may not edit, or that
+// would break the population cap, are rejected as a whole. The caller must
+// hold m.mutex.
+func (m *Manager) applyEditsLocked(edits []CellEdit, author Author) ([]CellChange, error) {
+	if err := m.checkEditZonesLocked(edits, author); err != nil {
+		return nil, err
+	}
+	if err := m.checkPopulationLocked(edits); err != nil {
+		return nil, err
+	}
+
+	changes := m.setCellsLocked(edits, author)
+	m.record(changes, author)
+	return changes, nil
+}
+
+// setCellsLocked is applyEditsLocked without the journal. The caller must
+// hold m.mutex.
+func (m *Manager) setCellsLocked(edits []CellEdit, author Author) []CellChange {
+	changes := make([]CellChange, 0, len(edits))
+	for _, e := range edits {
+		if !m.topology.Contains(e.Cell.X, e.Cell.Y) {
+			continue
+		}
+		if m.setCellLocked(e.Cell, e.Alive, author) {
+			changes = append(changes, CellChange{Cell: e.Cell, Before: !e.Alive, After: e.Alive})
+		}
+	}
+	return changes
+}
+
+// setCellLocked updates one cell and notifies observers. It reports whether
+// the cell changed. The caller must hold m.mutex.
+func (m *Manager) setCellLocked(c grid.Cell, alive bool, author Author) bool {
+	gr := m.game.GetGrid()
+	if gr.IsAlive(c.X, c.Y) == alive {
+		return false
+	}
+
+	if alive {
+		gr.SetCell(c.X, c.Y)
+		m.stateHash += cellHash(c)
+		m.extents.add(c)
+		m.notifyObservers(SetCellEvent{Cell: c, Author: author})
+	} else {
+		gr.ClearCell(c.X, c.Y)
+		m.stateHash -= cellHash(c)
+		m.extents.remove(c)
+		m.notifyObservers(ClearCellEvent{Cell: c, Author: author})
+	}
+	return true
+}
+
+// liveCellsInLocked returns the live cells inside rect. The caller must hold
+// m.mutex.
+func (m *Manager) liveCellsInLocked(rect grid.Rectangle) []grid.Cell {
+	return m.game.GetGrid().Subgrid(*rect.Normalized()).GetCells()
+}
+
+// The edit commands below are queued and applied between two ticks; see
+// submit. Each returns the generation the edit took effect at and the cells
+// that actually changed, or an error wrapping ErrZoneProtected or
+// ErrPopulationCap when the edit was rejected.
+
+// EditCells sets every cell to alive.
+func (m *Manager) EditCells(cells []grid.Cell, alive bool, author Author) (EditResult, error) {
+	edits := make([]CellEdit, len(cells))
+	for i, c := range cells {
+		edits[i] = CellEdit{Cell: c, Alive: alive}
+	}
+
+	return m.submit(func() ([]CellChange, error) {
+		return m.applyEditsLocked(edits, author)
+	})
+}
+
+// ToggleCells flips the state of every cell. A cell listed twice is toggled
+// once.
+func (m *Manager) ToggleCells(cells []grid.Cell, author Author) (EditResult, error) {
+	return m.submit(func() ([]CellChange, error) {
+		gr := m.game.GetGrid()
+		seen := make(map[grid.Cell]struct{}, len(cells))
+		edits := make([]CellEdit, 0, len(cells))
+		for _, c := range cells {
+			if _, ok := seen[c]; ok {
+				continue
+			}
+			seen[c] = struct{}{}
+			edits = append(edits, CellEdit{Cell: c, Alive: !gr.IsAlive(c.X, c.Y)})
+		}
+		return m.applyEditsLocked(edits, author)
+	})
+}
+
+// ReplaceRegion clears every live cell inside bounds that is not in cells
+// and sets cells, as one edit.
+func (m *Manager) ReplaceRegion(bounds grid.Rectangle, cells []grid.Cell, author Author) (EditResult, error) {
+	return m.submit(func() ([]CellChange, error) {
+		if err := m.CheckZonesRect(bounds, author); err != nil {
+			return nil, err
+		}
+		return m.applyEditsLocked(replaceEdits(m.liveCellsInLocked(bounds), cells), author)
+	})
+}
+
+// replaceEdits returns the edits turning the live cells of a region into
+// exactly cells.
+func replaceEdits(live []grid.Cell, cells []grid.Cell) []CellEdit {
+	keep := make(map[grid.Cell]struct{}, len(cells))
+	for _, c := range cells {
+		keep[c] = struct{}{}
+	}
+
+	edits := make([]CellEdit, 0, len(live)+len(cells))
+	for _, c := range live {
+		if _, ok := keep[c]; !ok {
+			edits = append(edits, CellEdit{Cell: c, Alive: false})
+		}
+	}
+	for _, c := range cells {
+		edits = append(edits, CellEdit{Cell: c, Alive: true})
+	}
+	return edits
+}
+
+// Stamp combines already transformed pattern cells, covering bounds, with
+// the grid according to mode.
+func (m *Manager) Stamp(cells []grid.Cell, bounds grid.Rectangle, mode StampMode, author Author) (EditResult, error) {
+	return m.submit(func() ([]CellChange, error) {
+		if err := m.CheckZonesRect(bounds, author); err != nil {
+			return nil, err
+		}
+		return m.stampLocked(cells, bounds, mode, author)
+	})
+}
+
+func (m *Manager) stampLocked(cells []grid.Cell, bounds grid.Rectangle, mode StampMode, author Author) ([]CellChange, error) {
+	gr := m.game.GetGrid()
+	var edits []CellEdit
+
+	switch mode {
+	case StampModeXor:
+		seen := make(map[grid.Cell]struct{}, len(cells))
+		for _, c := range cells {
+			if _, ok := seen[c]; ok {
+				continue
+			}
+			seen[c] = struct{}{}
+			edits = append(edits, CellEdit{Cell: c, Alive: !gr.IsAlive(c.X, c.Y)})
+		}
+	case StampModeAnd:
+		pattern := make(map[grid.Cell]struct{}, len(cells))
+		for _, c := range cells {
+			pattern[c] = struct{}{}
+		}
+		for _, c := range m.liveCellsInLocked(bounds) {
+			if _, ok := pattern[c]; !ok {
+				edits = append(edits, CellEdit{Cell: c, Alive: false})
+			}
+		}
+	case StampModeReplace:
+		edits = replaceEdits(m.liveCellsInLocked(bounds), cells)
+	default:
+		edits = make([]CellEdit, len(cells))
+		for i, c := range cells {
+			edits[i] = CellEdit{Cell: c, Alive: true}
+		}
+	}
+
+	return m.applyEditsLocked(edits, author)
+}
@@ -0,0 +1,175 @@
+package game
+
+import (
+	"sync/atomic"
+
+	"github.com/henilmalaviya/gol/grid"
+)
+
+// The manager applies ticks to the grid itself, so it broadcasts its own
+// events instead of relying on the grid's observers. Event types reuse the
+// grid's names so clients see the same protocol.
+
+// Author identifies who made an edit. The zero value means the server.
+// ID is the connection the edit came through, and is empty for callers
+// without one, such as gRPC clients.
+// Name is chosen freely by the client and only displayed; User is set when
+// the client proved it is that registered user, and is what zones check.
+// Admin is set for edits made with the admin token, which zones do not
+// stop; it is never sent to clients.
+type Author struct {
+	ID    string `json:"id"`
+	Name  string `json:"name,omitempty"`
+	Color string `json:"color,omitempty"`
+	User  string `json:"user,omitempty"`
+	Admin bool   `json:"-"`
+}
+
+func (a Author) IsZero() bool {
+	return a.ID == "" && a.Name == "" && a.User == ""
+}
+
+// ---
+
+type SetCellEvent struct {
+	Cell   grid.Cell
+	Author Author
+}
+
+func (e SetCellEvent) Type() grid.ObserverEventType {
+	return grid.SetCellEventType
+}
+
+// ---
+
+type ClearCellEvent struct {
+	Cell   grid.Cell
+	Author Author
+}
+
+func (e ClearCellEvent) Type() grid.ObserverEventType {
+	return grid.ClearCellEventType
+}
+
+// ---
+
+type TickEvent struct {
+	Generation int
+	BornCells  []grid.Cell
+	DiedCells  []grid.Cell
+}
+
+func (e TickEvent) Type() grid.ObserverEventType {
+	return grid.TickEventType
+}
+
+// ---
+
+type ClearGridEvent struct{}
+
+func (e ClearGridEvent) Type() grid.ObserverEventType {
+	return grid.ClearGridEventType
+}
+
+// ---
+
+// RegionObserver forwards manager events that touch its region. With period
+// detection enabled it also sends a PeriodEvent when its region becomes or
+// stops being periodic.
+type RegionObserver struct {
+	// region is read by connections while the manager moves it, so it is
+	// replaced whole rather than changed.
+	region     atomic.Pointer[grid.Rectangle]
+	updateFunc func(event grid.ObserverEvent)
+
+	// Updated from manager events, which are sent with the manager's mutex
+	// held.
+	hash     uint64
+	detector *PeriodDetector
+}
+
+func filterCells(cells []grid.Cell, region *grid.Rectangle) []grid.Cell {
+	var filtered []grid.Cell
+	for _, cell := range cells {
+		if cell.Inside(region) {
+			filtered = append(filtered, cell)
+		}
+	}
+	return filtered
+}
+
+func (o *RegionObserver) Update(event grid.ObserverEvent) {
+	region := o.GetRegion()
+
+	switch e := event.(type) {
+	case SetCellEvent:
+		if !e.Cell.Inside(&region) {
+			return
+		}
+		o.hash += cellHash(e.Cell)
+		o.updateFunc(e)
+	case ClearCellEvent:
+		if !e.Cell.Inside(&region) {
+			return
+		}
+		o.hash -= cellHash(e.Cell)
+		o.updateFunc(e)
+	case ClearGridEvent:
+		o.hash = 0
+		o.updateFunc(e)
+	case TickEvent:
+		filteredBorn, filteredDied := filterCells(e.BornCells, &region), filterCells(e.DiedCells, &region)
+		if len(filteredBorn) > 0 || len(filteredDied) > 0 {
+			o.updateFunc(TickEvent{
+				Generation: e.Generation,
+				BornCells:  filteredBorn,
+				DiedCells:  filteredDied,
+			})
+		}
+
+		// A tick that does not touch the region still counts as a
+		// generation of it.
+		if o.detector != nil {
+			o.hash = updateStateHash(o.hash, filteredBorn, filteredDied)
+			if o.detector.Observe(e.Generation, o.hash) {
+				o.updateFunc(PeriodEvent{
+					Generation:   e.Generation,
+					Period:       o.detector.Period,
+					StabilizedAt: o.detector.StabilizedAt,
+					Region:       &region,
+				})
+			}
+		}
+	default:
+		o.updateFunc(event)
+	}
+}
+
+func (o *RegionObserver) SetRegion(region grid.Rectangle) {
+	o.region.Store(&region)
+}
+
+func (o *RegionObserver) GetRegion() grid.Rectangle {
+	return *o.region.Load()
+}
+
+// WatchRegion moves a region observer to region. A positive periodWindow
+// enables period detection for the region, starting from its current state.
+func (m *Manager) WatchRegion(o *RegionObserver, region grid.Rectangle, periodWindow int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	o.SetRegion(region)
+	o.hash = 0
+	o.detector = nil
+	if periodWindow > 0 {
+		o.hash = StateHash(m.liveCellsInLocked(region))
+		o.detector = NewPeriodDetector(periodWindow)
+	}
+}
+
+func NewRegionObserver(region grid.Rectangle, updateFunc func(event grid.ObserverEvent)) *RegionObserver {
+	o := &RegionObserver{updateFunc: updateFunc}
+	o.SetRegion(region)
+	return o
+}
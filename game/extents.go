@@ -0,0 +1,208 @@
+package game
+
+import (
+	"sort"
+
+	"github.com/henilmalaviya/gol/grid"
+)
+
+// extents tracks the bounding box of the live cells from the cells that are
+// added and removed. Each row and column keeps a count of its live cells, so
+// when an edge row or column empties the box is marked stale and recomputed
+// from the occupied rows and columns the next time it is read. Cells can be
+// arbitrarily far apart, so the edges are never walked in.
+type extents struct {
+	rows   map[int]int
+	cols   map[int]int
+	count  int
+	bounds grid.Rectangle
+	stale  bool
+}
+
+func newExtents() extents {
+	return extents{
+		rows: make(map[int]int),
+		cols: make(map[int]int),
+	}
+}
+
+func (e *extents) add(c grid.Cell) {
+	if e.count == 0 {
+		e.bounds = grid.Rectangle{X1: c.X, Y1: c.Y, X2: c.X, Y2: c.Y}
+	} else {
+		e.bounds.X1, e.bounds.X2 = min(e.bounds.X1, c.X), max(e.bounds.X2, c.X)
+		e.bounds.Y1, e.bounds.Y2 = min(e.bounds.Y1, c.Y), max(e.bounds.Y2, c.Y)
+	}
+	e.count++
+	e.rows[c.Y]++
+	e.cols[c.X]++
+}
+
+func (e *extents) remove(c grid.Cell) {
+	e.count--
+	if e.rows[c.Y]--; e.rows[c.Y] == 0 {
+		delete(e.rows, c.Y)
+		if c.Y == e.bounds.Y1 || c.Y == e.bounds.Y2 {
+			e.stale = true
+		}
+	}
+	if e.cols[c.X]--; e.cols[c.X] == 0 {
+		delete(e.cols, c.X)
+		if c.X == e.bounds.X1 || c.X == e.bounds.X2 {
+			e.stale = true
+		}
+	}
+	if e.count == 0 {
+		e.bounds = grid.Rectangle{}
+		e.stale = false
+	}
+}
+
+// rect returns the bounding box, recomputing it if an edge emptied since it
+// was last read.
+func (e *extents) rect() grid.Rectangle {
+	if !e.stale {
+		return e.bounds
+	}
+
+	first := true
+	for y := range e.rows {
+		if first {
+			e.bounds.Y1, e.bounds.Y2 = y, y
+			first = false
+		}
+		e.bounds.Y1, e.bounds.Y2 = min(e.bounds.Y1, y), max(e.bounds.Y2, y)
+	}
+	first = true
+	for x := range e.cols {
+		if first {
+			e.bounds.X1, e.bounds.X2 = x, x
+			first = false
+		}
+		e.bounds.X1, e.bounds.X2 = min(e.bounds.X1, x), max(e.bounds.X2, x)
+	}
+	e.stale = false
+	return e.bounds
+}
+
+// apply updates the extents with the changes of a tick.
+func (e *extents) apply(born, died []grid.Cell) {
+	for _, c := range born {
+		e.add(c)
+	}
+	for _, c := range died {
+		e.remove(c)
+	}
+}
+
+func (e *extents) reset(cells []grid.Cell) {
+	*e = newExtents()
+	for _, c := range cells {
+		e.add(c)
+	}
+}
+
+// nested returns the bounding box as [[x1, y1], [x2, y2]], or nil when no
+// cell is alive.
+func (e *extents) nested() *[2][2]int {
+	if e.count == 0 {
+		return nil
+	}
+	r := e.rect()
+	b := r.ToNestedArray()
+	return &b
+}
+
+// reindexLocked recomputes the state hash and the extents from the grid,
+// after the grid was changed without going through the manager. The caller
+// must hold m.mutex.
+func (m *Manager) reindexLocked() {
+	cells := m.game.GetGrid().GetCells()
+	m.stateHash = StateHash(cells)
+	m.extents.reset(cells)
+}
+
+// Bounds returns the bounding box of the live cells. ok is false when no
+// cell is alive.
+func (m *Manager) Bounds() (bounds grid.Rectangle, ok bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.extents.rect(), m.extents.count > 0
+}
+
+// Cluster is a group of live cells close to each other.
+type Cluster struct {
+	Bounds     [2][2]int `json:"bounds"`
+	Center     [2]int    `json:"center"`
+	Population int       `json:"population"`
+}
+
+// Locate groups the live cells into clusters and returns up to limit of
+// them, most populated first. Cells are grouped by the chunks of the tick
+// engine and chunks that touch form one cluster, so clusters are at least a
+// chunk apart.
+func (m *Manager) Locate(limit int) []Cluster {
+	m.mutex.Lock()
+	cells := m.game.GetGrid().GetCells()
+	m.mutex.Unlock()
+
+	type square struct{ X, Y int }
+	squares := make(map[square][]grid.Cell)
+	for _, c := range cells {
+		key := square{X: c.X >> chunkShift, Y: c.Y >> chunkShift}
+		squares[key] = append(squares[key], c)
+	}
+
+	visited := make(map[square]bool, len(squares))
+	var clusters []Cluster
+	for start := range squares {
+		if visited[start] {
+			continue
+		}
+		visited[start] = true
+
+		b := grid.Rectangle{X1: squares[start][0].X, Y1: squares[start][0].Y, X2: squares[start][0].X, Y2: squares[start][0].Y}
+		population := 0
+		queue := []square{start}
+		for len(queue) > 0 {
+			sq := queue[0]
+			queue = queue[1:]
+			for _, c := range squares[sq] {
+				b.X1, b.X2 = min(b.X1, c.X), max(b.X2, c.X)
+				b.Y1, b.Y2 = min(b.Y1, c.Y), max(b.Y2, c.Y)
+			}
+			population += len(squares[sq])
+
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					n := square{X: sq.X + dx, Y: sq.Y + dy}
+					if _, ok := squares[n]; ok && !visited[n] {
+						visited[n] = true
+						queue = append(queue, n)
+					}
+				}
+			}
+		}
+
+		clusters = append(clusters, Cluster{
+			Bounds:     b.ToNestedArray(),
+			Center:     [2]int{(b.X1 + b.X2) / 2, (b.Y1 + b.Y2) / 2},
+			Population: population,
+		})
+	}
+
+	sort.Slice(clusters, func(i, j int) bool {
+		if clusters[i].Population != clusters[j].Population {
+			return clusters[i].Population > clusters[j].Population
+		}
+		if clusters[i].Bounds[0][1] != clusters[j].Bounds[0][1] {
+			return clusters[i].Bounds[0][1] < clusters[j].Bounds[0][1]
+		}
+		return clusters[i].Bounds[0][0] < clusters[j].Bounds[0][0]
+	})
+
+	if limit > 0 && len(clusters) > limit {
+		clusters = clusters[:limit]
+	}
+	return clusters
+}
@@ -0,0 +1,248 @@
+package game
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// The stats history keeps one sample per generation in a ring buffer, and
+// coarser levels that each aggregate historyFactor samples of the level
+// below, so longer windows can be served without keeping every generation.
+
+const (
+	historyFactor = 10
+	historyLevels = 3
+
+	// DefaultHistorySize is how many samples each level keeps unless
+	// configured otherwise.
+	DefaultHistorySize = 3600
+)
+
+// StatsSample describes one generation, or a run of Span generations
+// starting at Generation once downsampled. Population and TickMs are
+// averaged, Births and Deaths summed and Bounds merged. Bounds is nil while
+// the world is empty.
+type StatsSample struct {
+	Generation int        `json:"generation"`
+	Span       int        `json:"span"`
+	Time       time.Time  `json:"time"`
+	Population float64    `json:"population"`
+	Births     int        `json:"births"`
+	Deaths     int        `json:"deaths"`
+	Bounds     *[2][2]int `json:"bounds"`
+	TickMs     float64    `json:"tick_ms"`
+}
+
+func mergeBounds(a, b *[2][2]int) *[2][2]int {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	return &[2][2]int{
+		{min(a[0][0], b[0][0]), min(a[0][1], b[0][1])},
+		{max(a[1][0], b[1][0]), max(a[1][1], b[1][1])},
+	}
+}
+
+// aggregate merges consecutive samples into one.
+func aggregate(samples []StatsSample) StatsSample {
+	out := StatsSample{
+		Generation: samples[0].Generation,
+		Time:       samples[0].Time,
+	}
+
+	var population, tickMs float64
+	for _, s := range samples {
+		out.Span += s.Span
+		out.Births += s.Births
+		out.Deaths += s.Deaths
+		out.Bounds = mergeBounds(out.Bounds, s.Bounds)
+		population += s.Population * float64(s.Span)
+		tickMs += s.TickMs * float64(s.Span)
+	}
+	out.Population = population / float64(out.Span)
+	out.TickMs = tickMs / float64(out.Span)
+	return out
+}
+
+type historyLevel struct {
+	span    int
+	samples []StatsSample // ring buffer, oldest at next once full
+	next    int
+	pending []StatsSample // samples of the level below not aggregated yet
+}
+
+func (l *historyLevel) push(sample StatsSample, size int) {
+	if len(l.samples) < size {
+		l.samples = append(l.samples, sample)
+		return
+	}
+	l.samples[l.next] = sample
+	l.next = (l.next + 1) % size
+}
+
+// ordered returns the level's samples, oldest first.
+func (l *historyLevel) ordered() []StatsSample {
+	out := make([]StatsSample, 0, len(l.samples))
+	out = append(out, l.samples[l.next:]...)
+	return append(out, l.samples[:l.next]...)
+}
+
+// StatsHistory is a downsampled time series of per-generation stats.
+type StatsHistory struct {
+	size   int
+	levels [historyLevels]historyLevel
+
+	mutex sync.Mutex
+}
+
+// NewStatsHistory keeps size samples at each level. A size of zero
+// disables the history.
+func NewStatsHistory(size int) *StatsHistory {
+	h := &StatsHistory{size: size}
+	span := 1
+	for i := range h.levels {
+		h.levels[i].span = span
+		span *= historyFactor
+	}
+	return h
+}
+
+// Record adds a generation's sample.
+func (h *StatsHistory) Record(sample StatsSample) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.size <= 0 {
+		return
+	}
+
+	sample.Span = 1
+	for i := range h.levels {
+		l := &h.levels[i]
+		l.push(sample, h.size)
+
+		if i+1 == len(h.levels) {
+			break
+		}
+		// Buckets cover generations k*span+1 to (k+1)*span, so that
+		// they line up across levels and restarts.
+		next := &h.levels[i+1]
+		next.pending = append(next.pending, sample)
+		if (sample.Generation+sample.Span-1)%next.span != 0 {
+			break
+		}
+		sample = aggregate(next.pending)
+		next.pending = next.pending[:0]
+	}
+}
+
+// Reset drops every sample, for when the generation count jumps.
+func (h *StatsHistory) Reset() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for i := range h.levels {
+		l := &h.levels[i]
+		l.samples, l.next, l.pending = nil, 0, nil
+	}
+}
+
+// pickLevel returns the index of the level to start a query from: the
+// coarsest level that is no coarser than resolution and still holds from.
+// If there is none, the finest level that holds from is used, and failing
+// that the coarsest one with samples.
+func (h *StatsHistory) pickLevel(from, resolution int) int {
+	pick, fallback := -1, 0
+	for i := range h.levels {
+		l := &h.levels[i]
+		if len(l.samples) == 0 {
+			continue
+		}
+		fallback = i
+
+		if l.ordered()[0].Generation > from {
+			continue
+		}
+		if pick == -1 || l.span <= resolution {
+			pick = i
+		}
+	}
+	if pick == -1 {
+		return fallback
+	}
+	return pick
+}
+
+// Query returns the samples for generations from to to inclusive, each
+// covering resolution generations. Older ranges come back coarser than
+// asked for once their fine samples are gone.
+func (h *StatsHistory) Query(from, to, resolution int) ([]StatsSample, error) {
+	if from > to {
+		return nil, fmt.Errorf("from must not be after to")
+	}
+	resolution = max(resolution, 1)
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	// A coarse level lags behind the finer ones by the samples it has not
+	// aggregated yet, so each finer level fills in the tail the levels
+	// above it lack.
+	var samples []StatsSample
+	covered := from - 1 // the last generation the samples cover
+	for i := h.pickLevel(from, resolution); i >= 0; i-- {
+		for _, s := range h.levels[i].ordered() {
+			end := s.Generation + s.Span - 1
+			if end <= covered || s.Generation > to || len(samples) > 0 && s.Generation <= covered {
+				continue
+			}
+			samples = append(samples, s)
+			covered = end
+		}
+	}
+
+	// Group the samples into buckets of resolution generations. Samples
+	// already coarser than that stay as they are.
+	var out []StatsSample
+	start := 0
+	for i := 1; i <= len(samples); i++ {
+		if i == len(samples) || (samples[i].Generation-1)/resolution != (samples[start].Generation-1)/resolution {
+			if i-start == 1 {
+				out = append(out, samples[start])
+			} else {
+				out = append(out, aggregate(samples[start:i]))
+			}
+			start = i
+		}
+	}
+	return out, nil
+}
+
+// StatsHistory returns the manager's stats history.
+func (m *Manager) StatsHistory() *StatsHistory {
+	return m.history
+}
+
+// SetHistorySize sets how many samples each level of the stats history
+// keeps. It must be called before Start.
+func (m *Manager) SetHistorySize(size int) {
+	m.history = NewStatsHistory(size)
+}
+
+// recordHistoryLocked samples the generation that was just computed. The
+// caller must hold m.mutex.
+func (m *Manager) recordHistoryLocked(births, deaths int, duration time.Duration) {
+	m.history.Record(StatsSample{
+		Generation: m.stats.Generation,
+		Time:       time.Now(),
+		Population: float64(m.extents.count),
+		Births:     births,
+		Deaths:     deaths,
+		Bounds:     m.extents.nested(),
+		TickMs:     float64(duration.Microseconds()) / 1000,
+	})
+}
@@ -0,0 +1,198 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+// recordGenerations records generations 1 to n, each with one birth.
+func recordGenerations(h *StatsHistory, n int) {
+	for gen := 1; gen <= n; gen++ {
+		h.Record(StatsSample{Generation: gen, Population: float64(gen), Births: 1})
+	}
+}
+
+// spans lists each sample as its first generation and span.
+func spans(samples []StatsSample) [][2]int {
+	out := make([][2]int, len(samples))
+	for i, s := range samples {
+		out[i] = [2]int{s.Generation, s.Span}
+	}
+	return out
+}
+
+func TestRecord(t *testing.T) {
+	tests := []struct {
+		name        string
+		size        int
+		generations int
+		want        [historyLevels][][2]int
+	}{
+		{
+			name:        "disabled",
+			size:        0,
+			generations: 20,
+		},
+		{
+			name:        "first bucket still pending",
+			size:        5,
+			generations: 3,
+			want:        [historyLevels][][2]int{{{1, 1}, {2, 1}, {3, 1}}},
+		},
+		{
+			name:        "ring buffer wraps",
+			size:        5,
+			generations: 12,
+			want: [historyLevels][][2]int{
+				{{8, 1}, {9, 1}, {10, 1}, {11, 1}, {12, 1}},
+				{{1, 10}},
+			},
+		},
+		{
+			name:        "every level",
+			size:        5,
+			generations: 120,
+			want: [historyLevels][][2]int{
+				{{116, 1}, {117, 1}, {118, 1}, {119, 1}, {120, 1}},
+				{{71, 10}, {81, 10}, {91, 10}, {101, 10}, {111, 10}},
+				{{1, 100}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewStatsHistory(tt.size)
+			recordGenerations(h, tt.generations)
+
+			for i := range h.levels {
+				got := spans(h.levels[i].ordered())
+				if len(got) == 0 && len(tt.want[i]) == 0 {
+					continue
+				}
+				if !reflect.DeepEqual(got, tt.want[i]) {
+					t.Errorf("level %d holds %v, want %v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRecordAggregates(t *testing.T) {
+	h := NewStatsHistory(5)
+	recordGenerations(h, 10)
+
+	got := h.levels[1].ordered()[0]
+	if got.Population != 5.5 || got.Births != 10 {
+		t.Errorf("got population %v and %d births, want 5.5 and 10", got.Population, got.Births)
+	}
+}
+
+func TestPickLevel(t *testing.T) {
+	tests := []struct {
+		name        string
+		generations int
+		from        int
+		resolution  int
+		want        int
+	}{
+		{name: "empty", generations: 0, from: 1, resolution: 1, want: 0},
+		{name: "finest holds from", generations: 120, from: 118, resolution: 1, want: 0},
+		{name: "coarser level asked for", generations: 120, from: 118, resolution: 10, want: 1},
+		{name: "coarsest no coarser than resolution", generations: 120, from: 80, resolution: 1000, want: 2},
+		{name: "finest that holds from", generations: 120, from: 80, resolution: 1, want: 1},
+		{name: "nothing holds from", generations: 120, from: 0, resolution: 1, want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewStatsHistory(5)
+			recordGenerations(h, tt.generations)
+
+			if got := h.pickLevel(tt.from, tt.resolution); got != tt.want {
+				t.Errorf("got level %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuery(t *testing.T) {
+	tests := []struct {
+		name        string
+		generations int
+		from        int
+		to          int
+		resolution  int
+		want        [][2]int
+	}{
+		{
+			name:        "finest level",
+			generations: 120,
+			from:        118,
+			to:          120,
+			resolution:  1,
+			want:        [][2]int{{118, 1}, {119, 1}, {120, 1}},
+		},
+		{
+			name:        "stitched across every level",
+			generations: 120,
+			from:        0,
+			to:          120,
+			resolution:  1,
+			want:        [][2]int{{1, 100}, {101, 10}, {111, 10}},
+		},
+		{
+			name:        "coarse level with a fine tail",
+			generations: 125,
+			from:        90,
+			to:          125,
+			resolution:  1,
+			want:        [][2]int{{81, 10}, {91, 10}, {101, 10}, {111, 10}, {121, 1}, {122, 1}, {123, 1}, {124, 1}, {125, 1}},
+		},
+		{
+			name:        "bucketed",
+			generations: 125,
+			from:        1,
+			to:          125,
+			resolution:  50,
+			want:        [][2]int{{1, 100}, {101, 25}},
+		},
+		{
+			name:        "window inside the coarse level",
+			generations: 120,
+			from:        20,
+			to:          30,
+			resolution:  1,
+			want:        [][2]int{{1, 100}},
+		},
+		{
+			name:        "nothing recorded",
+			generations: 0,
+			from:        1,
+			to:          10,
+			resolution:  1,
+			want:        [][2]int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewStatsHistory(5)
+			recordGenerations(h, tt.generations)
+
+			samples, err := h.Query(tt.from, tt.to, tt.resolution)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := spans(samples); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryInvalidRange(t *testing.T) {
+	if _, err := NewStatsHistory(5).Query(10, 1, 1); err == nil {
+		t.Error("got no error for from after to")
+	}
+}
@@ -0,0 +1,231 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/henilmalaviya/gol/grid"
+)
+
+var (
+	ErrNothingToUndo = errors.New("nothing to undo")
+	ErrNothingToRedo = errors.New("nothing to redo")
+	ErrEditTooOld    = errors.New("edit is too old")
+)
+
+// CellChange records a single cell edit.
+type CellChange struct {
+	Cell   grid.Cell
+	Before bool
+	After  bool
+}
+
+// JournalEntry is one edit command, made at Generation. Fill is set when
+// the command was a random fill.
+type JournalEntry struct {
+	Generation int
+	Changes    []CellChange
+	Fill       *RandomFill
+}
+
+// Journal keeps a user's recent edits so they can be undone and redone.
+type Journal struct {
+	undo  []JournalEntry
+	redo  []JournalEntry
+	limit int
+
+	mutex sync.Mutex
+}
+
+func NewJournal(limit int) *Journal {
+	return &Journal{
+		limit: limit,
+	}
+}
+
+func (j *Journal) push(stack []JournalEntry, entry JournalEntry) []JournalEntry {
+	stack = append(stack, entry)
+	if j.limit > 0 && len(stack) > j.limit {
+		stack = stack[len(stack)-j.limit:]
+	}
+	return stack
+}
+
+// key identifies the user behind an author: their verified user when they
+// proved one, so journals and scheduled edits survive reconnects, and their
+// connection otherwise. The display name is not used, as anyone can claim
+// it.
+func (a Author) key() string {
+	if a.User != "" {
+		return "user:" + a.User
+	}
+	return "id:" + a.ID
+}
+
+// journaled reports whether the author's edits are kept to be undone: those
+// made through a connection or by a verified user. Others could never undo
+// them, and their journal would never be dropped.
+func (a Author) journaled() bool {
+	return a.ID != "" || a.User != ""
+}
+
+// SetJournalLimits sets how many edits each journal keeps and how many
+// generations old an edit may be and still be undone or redone. Zero
+// disables the corresponding limit.
+func (m *Manager) SetJournalLimits(history, maxAge int) {
+	m.journalsMutex.Lock()
+	defer m.journalsMutex.Unlock()
+
+	m.journalHistory = history
+	m.journalMaxAge = maxAge
+}
+
+func (m *Manager) journalFor(author Author) *Journal {
+	m.journalsMutex.Lock()
+	defer m.journalsMutex.Unlock()
+
+	key := author.key()
+	journal, ok := m.journals[key]
+	if !ok {
+		journal = NewJournal(m.journalHistory)
+		m.journals[key] = journal
+	}
+	return journal
+}
+
+// DropJournal forgets the edits made through a connection without a
+// verified user. It is called when the connection closes.
+func (m *Manager) DropJournal(author Author) {
+	m.journalsMutex.Lock()
+	defer m.journalsMutex.Unlock()
+	delete(m.journals, Author{ID: author.ID}.key())
+}
+
+// record adds changes made by author to their journal. The caller must
+// hold m.mutex.
+func (m *Manager) record(changes []CellChange, author Author) {
+	m.recordEntry(JournalEntry{Generation: m.stats.Generation, Changes: changes}, author)
+}
+
+func (m *Manager) recordEntry(entry JournalEntry, author Author) {
+	if len(entry.Changes) == 0 || !author.journaled() {
+		return
+	}
+
+	journal := m.journalFor(author)
+	journal.mutex.Lock()
+	journal.undo = journal.push(journal.undo, entry)
+	journal.redo = nil
+	journal.mutex.Unlock()
+}
+
+// replay moves the cells of entry from one side of the change to the other.
+// Cells that no longer hold the expected state, because the simulation or
+// someone else changed them since, are skipped. The remaining cells go
+// through check, when it is not nil, the zones and the population cap, and
+// nothing is changed if any of them fails. The caller must hold m.mutex.
+func (m *Manager) replay(entry JournalEntry, forward bool, author Author, check func([]grid.Cell) error) (JournalEntry, int, error) {
+	gr := m.game.GetGrid()
+
+	applied := JournalEntry{Generation: m.stats.Generation}
+	var cells []grid.Cell
+	var edits []CellEdit
+	skipped := 0
+	for _, ch := range entry.Changes {
+		from, to := ch.After, ch.Before
+		if forward {
+			from, to = ch.Before, ch.After
+		}
+
+		if gr.IsAlive(ch.Cell.X, ch.Cell.Y) != from {
+			skipped++
+			continue
+		}
+		applied.Changes = append(applied.Changes, ch)
+		cells = append(cells, ch.Cell)
+		edits = append(edits, CellEdit{Cell: ch.Cell, Alive: to})
+	}
+
+	if check != nil {
+		if err := check(cells); err != nil {
+			return JournalEntry{}, 0, err
+		}
+	}
+	if err := m.checkEditZonesLocked(edits, author); err != nil {
+		return JournalEntry{}, 0, err
+	}
+	if err := m.checkPopulationLocked(edits); err != nil {
+		return JournalEntry{}, 0, err
+	}
+
+	for _, e := range edits {
+		m.setCellLocked(e.Cell, e.Alive, author)
+	}
+	return applied, skipped, nil
+}
+
+func (m *Manager) undoRedo(author Author, forward bool, check func([]grid.Cell) error) (EditResult, int, error) {
+	journal := m.journalFor(author)
+
+	m.journalsMutex.Lock()
+	maxAge := m.journalMaxAge
+	m.journalsMutex.Unlock()
+
+	var skipped int
+
+	// submit runs the closure with m.mutex held, so the lock order is
+	// m.mutex before journal.mutex, as in record.
+	result, err := m.submit(func() ([]CellChange, error) {
+		journal.mutex.Lock()
+		defer journal.mutex.Unlock()
+
+		from, to := &journal.undo, &journal.redo
+		if forward {
+			from, to = &journal.redo, &journal.undo
+		}
+
+		if len(*from) == 0 {
+			if forward {
+				return nil, ErrNothingToRedo
+			}
+			return nil, ErrNothingToUndo
+		}
+
+		entry := (*from)[len(*from)-1]
+		if age := m.stats.Generation - entry.Generation; maxAge > 0 && age > maxAge {
+			// Everything below is older still.
+			*from = nil
+			return nil, fmt.Errorf("%w: made %d generations ago, limit is %d", ErrEditTooOld, age, maxAge)
+		}
+
+		applied, n, err := m.replay(entry, forward, author, check)
+		if err != nil {
+			// The entry stays, to be retried once the edit is allowed.
+			return nil, err
+		}
+		skipped = n
+		*from = (*from)[:len(*from)-1]
+
+		if len(applied.Changes) > 0 {
+			*to = journal.push(*to, applied)
+		}
+		return applied.Changes, nil
+	})
+
+	return result, skipped, err
+}
+
+// Undo reverts the author's most recent edit. It also returns how many cells
+// were skipped because they changed since the edit. check, when not nil,
+// vets the cells about to change, as for a new edit by author; the undo is
+// rejected if it, a protected zone or the population cap fails.
+func (m *Manager) Undo(author Author, check func([]grid.Cell) error) (EditResult, int, error) {
+	return m.undoRedo(author, false, check)
+}
+
+// Redo reapplies the author's most recently undone edit, with the same
+// checks as Undo.
+func (m *Manager) Redo(author Author, check func([]grid.Cell) error) (EditResult, int, error) {
+	return m.undoRedo(author, true, check)
+}
@@ -0,0 +1,169 @@
+package game
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/henilmalaviya/gol/grid"
+)
+
+var (
+	alice = Author{ID: "c1", Name: "alice"}
+	bob   = Author{ID: "c2", Name: "bob"}
+)
+
+// alive returns which of cells are alive in the manager's grid.
+func alive(m *Manager, cells ...grid.Cell) []bool {
+	gr := m.GetGame().GetGrid()
+	states := make([]bool, len(cells))
+	for i, c := range cells {
+		states[i] = gr.IsAlive(c.X, c.Y)
+	}
+	return states
+}
+
+func TestUndoRedo(t *testing.T) {
+	m := NewManager()
+	a, b := grid.Cell{X: 0, Y: 0}, grid.Cell{X: 5, Y: 5}
+	m.EditCells([]grid.Cell{a, b}, true, alice)
+
+	result, skipped, err := m.Undo(alice, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Changes) != 2 || skipped != 0 {
+		t.Errorf("undo changed %d cells and skipped %d, want 2 and 0", len(result.Changes), skipped)
+	}
+	if got := alive(m, a, b); got[0] || got[1] {
+		t.Errorf("after undo cells are alive: %v", got)
+	}
+
+	result, skipped, err = m.Redo(alice, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Changes) != 2 || skipped != 0 {
+		t.Errorf("redo changed %d cells and skipped %d, want 2 and 0", len(result.Changes), skipped)
+	}
+	if got := alive(m, a, b); !got[0] || !got[1] {
+		t.Errorf("after redo cells are dead: %v", got)
+	}
+
+	// The redone edit can be undone again.
+	if _, _, err := m.Undo(alice, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := alive(m, a, b); got[0] || got[1] {
+		t.Errorf("after the second undo cells are alive: %v", got)
+	}
+}
+
+func TestNewEditClearsRedo(t *testing.T) {
+	m := NewManager()
+	m.EditCells([]grid.Cell{{X: 0, Y: 0}}, true, alice)
+	if _, _, err := m.Undo(alice, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	m.EditCells([]grid.Cell{{X: 1, Y: 1}}, true, alice)
+
+	if _, _, err := m.Redo(alice, nil); !errors.Is(err, ErrNothingToRedo) {
+		t.Fatalf("redo after a new edit: got %v, want %v", err, ErrNothingToRedo)
+	}
+	if got := alive(m, grid.Cell{X: 0, Y: 0}); got[0] {
+		t.Error("the undone edit was reapplied")
+	}
+}
+
+func TestUndoSkipsChangedCells(t *testing.T) {
+	m := NewManager()
+	a, b := grid.Cell{X: 0, Y: 0}, grid.Cell{X: 5, Y: 5}
+	m.EditCells([]grid.Cell{a, b}, true, alice)
+
+	// A cell someone else killed and brought back is as alice left it, so
+	// it is still undone.
+	m.EditCells([]grid.Cell{a}, false, bob)
+	m.EditCells([]grid.Cell{a}, true, bob)
+
+	result, skipped, err := m.Undo(alice, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Changes) != 2 || skipped != 0 {
+		t.Fatalf("undo changed %d cells and skipped %d, want 2 and 0", len(result.Changes), skipped)
+	}
+
+	// A cell someone else killed since is skipped.
+	m.EditCells([]grid.Cell{a, b}, true, alice)
+	m.EditCells([]grid.Cell{b}, false, bob)
+
+	result, skipped, err = m.Undo(alice, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Changes) != 1 || result.Changes[0].Cell != a || skipped != 1 {
+		t.Errorf("undo changed %v and skipped %d, want only %v and 1", result.Changes, skipped, a)
+	}
+	if got := alive(m, a, b); got[0] || got[1] {
+		t.Errorf("after undo cells are alive: %v", got)
+	}
+
+	// Bob's own journal is untouched.
+	if _, _, err := m.Undo(bob, nil); err != nil {
+		t.Errorf("bob's undo: %v", err)
+	}
+}
+
+func TestUndoEmpty(t *testing.T) {
+	m := NewManager()
+
+	if _, _, err := m.Undo(alice, nil); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("undo: got %v, want %v", err, ErrNothingToUndo)
+	}
+	if _, _, err := m.Redo(alice, nil); !errors.Is(err, ErrNothingToRedo) {
+		t.Errorf("redo: got %v, want %v", err, ErrNothingToRedo)
+	}
+
+	// Another connection claiming the same name has its own journal.
+	m.EditCells([]grid.Cell{{X: 0, Y: 0}}, true, alice)
+	if _, _, err := m.Undo(Author{ID: "c3", Name: "alice"}, nil); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("undo by name: got %v, want %v", err, ErrNothingToUndo)
+	}
+}
+
+func TestUndoRejected(t *testing.T) {
+	m := NewManager()
+	m.EditCells([]grid.Cell{{X: 0, Y: 0}}, true, alice)
+
+	rejected := errors.New("rejected")
+	if _, _, err := m.Undo(alice, func([]grid.Cell) error { return rejected }); !errors.Is(err, rejected) {
+		t.Fatalf("got %v, want %v", err, rejected)
+	}
+	if got := alive(m, grid.Cell{X: 0, Y: 0}); !got[0] {
+		t.Fatal("a rejected undo changed the cell")
+	}
+
+	// The edit stays in the journal.
+	if _, _, err := m.Undo(alice, nil); err != nil {
+		t.Errorf("undo after the rejection: %v", err)
+	}
+}
+
+func TestUndoWithoutConnection(t *testing.T) {
+	m := NewManager()
+
+	// A caller without a connection, as over gRPC, has no journal unless
+	// it is a verified user, who shares theirs across connections.
+	m.EditCells([]grid.Cell{{X: 0, Y: 0}}, true, Author{Name: "alice"})
+	if _, _, err := m.Undo(Author{Name: "alice"}, nil); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("anonymous undo: got %v, want %v", err, ErrNothingToUndo)
+	}
+
+	m.EditCells([]grid.Cell{{X: 1, Y: 1}}, true, Author{Name: "alice", User: "alice"})
+	if _, _, err := m.Undo(Author{ID: "c5", Name: "alice", User: "alice"}, nil); err != nil {
+		t.Errorf("undo from a connection of the same user: %v", err)
+	}
+	if got := alive(m, grid.Cell{X: 0, Y: 0}, grid.Cell{X: 1, Y: 1}); !got[0] || got[1] {
+		t.Errorf("cells alive: %v, want [true false]", got)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/henilmalaviya/gol"
+	"github.com/henilmalaviya/gol/grid"
 	"github.com/henilmalaviya/golw/util"
 )
 
@@ -12,6 +13,19 @@ type GameStats struct {
 	Generation int `json:"generation"`
 	BirthCount int `json:"birth_count"`
 	DeathCount int `json:"death_count"`
+	// CulledCount counts cells removed by the population cull action.
+	CulledCount int `json:"culled_count"`
+	// Population and Bounds describe the live cells. Bounds is nil while no
+	// cell is alive.
+	Population int        `json:"population"`
+	Bounds     *[2][2]int `json:"bounds"`
+	// Period is the world's period once a state repeats, 1 for a still life
+	// and 0 while it is not periodic. StabilizedAt is the first generation
+	// of the repeating cycle.
+	Period       int `json:"period"`
+	StabilizedAt int `json:"stabilized_at"`
+
+	TickStats
 }
 
 func (s *GameStats) IncrementGeneration() {
@@ -26,8 +40,12 @@ func (s *GameStats) IncrementDeaths(count int) {
 	s.DeathCount += count
 }
 
+// LoadFromSnapshot restores the counters from a snapshot. Tick timing
+// describes the running process, so it is kept as is.
 func (s *GameStats) LoadFromSnapshot(snapshot *GameStats) {
+	timing := s.TickStats
 	*s = *snapshot
+	s.TickStats = timing
 }
 
 /* -------------------------------------------------------------------------- */
@@ -36,7 +54,39 @@ type Manager struct {
 	game  *gol.Game
 	stats GameStats
 
-	ticker *time.Ticker
+	topology    Topology
+	limits      PopulationLimits
+	alertLevel  AlertLevel
+	paused      bool
+	stop        chan struct{}
+	intervals   chan time.Duration // tick interval changes for the tick loop
+	edits       editQueue
+	scheduler   tickScheduler
+	tickWorkers int
+
+	// stateHash is the StateHash of the live cells, kept up to date by
+	// every change so period detection does not rehash the grid.
+	stateHash uint64
+	extents   extents
+	period    *PeriodDetector
+	history   *StatsHistory
+
+	observers      map[grid.Observer]struct{}
+	observersMutex sync.RWMutex
+
+	schedule map[string]ScheduledEdit
+
+	census       *Census
+	censusTicker *time.Ticker
+	censusMutex  sync.Mutex
+
+	zones      map[string]Zone
+	zonesMutex sync.Mutex
+
+	journals       map[string]*Journal
+	journalHistory int
+	journalMaxAge  int
+	journalsMutex  sync.Mutex
 
 	mutex sync.Mutex
 }
@@ -46,48 +96,220 @@ func (m *Manager) GetGame() *gol.Game {
 }
 
 func (m *Manager) GetStats() GameStats {
-	return m.stats
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.statsLocked()
+}
+
+func (m *Manager) statsLocked() GameStats {
+	stats := m.stats
+	stats.Population = m.extents.count
+	stats.Bounds = m.extents.nested()
+	return stats
+}
+
+func (m *Manager) AddObserver(observer grid.Observer) {
+	m.observersMutex.Lock()
+	defer m.observersMutex.Unlock()
+	m.observers[observer] = struct{}{}
+}
+
+func (m *Manager) RemoveObserver(observer grid.Observer) {
+	m.observersMutex.Lock()
+	defer m.observersMutex.Unlock()
+	delete(m.observers, observer)
+}
+
+func (m *Manager) notifyObservers(event grid.ObserverEvent) {
+	m.observersMutex.RLock()
+	defer m.observersMutex.RUnlock()
+	for observer := range m.observers {
+		observer.Update(event)
+	}
+}
+
+func (m *Manager) GetTopology() Topology {
+	return m.topology
+}
+
+// SetTopology changes the shape of the world. Live cells outside the new
+// bounds are removed. It must be called before Start.
+func (m *Manager) SetTopology(topology Topology) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.topology = topology
+	if !topology.IsBounded() {
+		return
+	}
+
+	gr := m.game.GetGrid()
+	for _, c := range gr.GetCells() {
+		if !topology.Contains(c.X, c.Y) {
+			gr.ClearCell(c.X, c.Y)
+		}
+	}
+	m.reindexLocked()
+}
+
+// CheckCells returns an error if any of the cells is outside the world.
+func (m *Manager) CheckCells(cells []grid.Cell) error {
+	for _, c := range cells {
+		if err := m.topology.Check(c.X, c.Y); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Manager) SetCell(x, y int, author Author) (EditResult, error) {
+	if err := m.topology.Check(x, y); err != nil {
+		return EditResult{}, err
+	}
+	return m.EditCells([]grid.Cell{{X: x, Y: y}}, true, author)
+}
+
+func (m *Manager) ClearCell(x, y int, author Author) (EditResult, error) {
+	if err := m.topology.Check(x, y); err != nil {
+		return EditResult{}, err
+	}
+	return m.EditCells([]grid.Cell{{X: x, Y: y}}, false, author)
+}
+
+// Tick advances the game by one generation, computing it across the
+// manager's tick workers, and returns the born and died cells.
+func (m *Manager) Tick() ([]grid.Cell, []grid.Cell) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	start := time.Now()
+	gr := m.game.GetGrid()
+	bornCells, diedCells := m.topology.ComputeNextGeneration(gr.GetCells(), m.tickWorkers)
+
+	if len(bornCells) > 0 {
+		coords := make([][2]int, len(bornCells))
+		for i, c := range bornCells {
+			coords[i] = [2]int{c.X, c.Y}
+		}
+		gr.SetCells(coords)
+	}
+	for _, c := range diedCells {
+		gr.ClearCell(c.X, c.Y)
+	}
+
+	m.stats.IncrementGeneration()
+	m.stats.IncrementBirths(len(bornCells))
+	m.stats.IncrementDeaths(len(diedCells))
+
+	removedCells := diedCells
+	if culled := m.enforcePopulationLimits(); len(culled) > 0 {
+		removedCells = append(diedCells[:len(diedCells):len(diedCells)], culled...)
+	}
+
+	m.notifyObservers(TickEvent{
+		Generation: m.stats.Generation,
+		BornCells:  bornCells,
+		DiedCells:  removedCells,
+	})
+
+	m.stateHash = updateStateHash(m.stateHash, bornCells, removedCells)
+	m.extents.apply(bornCells, removedCells)
+
+	// Scheduled edits are part of the new generation, so observers see them
+	// after the tick that produced it, and the state is hashed and recorded
+	// with them.
+	m.runScheduledEditsLocked()
+
+	m.detectPeriodLocked()
+	m.recordHistoryLocked(len(bornCells), len(removedCells), time.Since(start))
+
+	return bornCells, diedCells
+}
+
+// SetTickWorkers sets the number of goroutines used to compute a tick.
+// Values below one fall back to DefaultTickWorkers.
+func (m *Manager) SetTickWorkers(workers int) {
+	if workers < 1 {
+		workers = DefaultTickWorkers()
+	}
+
+	m.mutex.Lock()
+	m.tickWorkers = workers
+	m.mutex.Unlock()
+}
+
+// SetTickPolicy sets how the tick loop reacts to ticks that overrun the
+// tick interval. maxCatchUp bounds how many ticks TickPolicyCatchUp may run
+// back to back. It must be called before Start.
+func (m *Manager) SetTickPolicy(policy TickPolicy, maxCatchUp int) {
+	m.scheduler.policy = policy
+	m.scheduler.maxCatchUp = max(maxCatchUp, 0)
+}
+
+// Pause stops the tick loop from advancing generations without stopping it.
+func (m *Manager) Pause() {
+	m.mutex.Lock()
+	m.paused = true
+	m.mutex.Unlock()
+}
+
+func (m *Manager) Resume() {
+	m.mutex.Lock()
+	m.paused = false
+	m.mutex.Unlock()
+}
+
+func (m *Manager) IsPaused() bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.paused
 }
 
 func (m *Manager) Start(tickInterval time.Duration) {
-	if m.ticker != nil {
+	if m.stop != nil {
 		return // Already started
 	}
 
 	logger := util.GetLogger()
-	logger.Info("Starting game tick loop", "interval_ms", tickInterval.Milliseconds())
-
-	m.ticker = time.NewTicker(tickInterval)
-
-	go func() {
-		for range m.ticker.C {
-			m.mutex.Lock()
-			bornCells, diedCells := m.game.GetGrid().Tick()
-			m.stats.IncrementGeneration()
-			m.stats.IncrementBirths(len(bornCells))
-			m.stats.IncrementDeaths(len(diedCells))
-			m.mutex.Unlock()
-		}
-	}()
+	logger.Info("Starting game tick loop", "interval_ms", tickInterval.Milliseconds(), "workers", m.tickWorkers, "policy", m.scheduler.policy)
+
+	m.scheduler.baseInterval = tickInterval
+	m.scheduler.interval = tickInterval
+	m.stop = make(chan struct{})
+	m.openEditQueue()
+
+	go m.runTickLoop(m.stop)
 }
 
 func (m *Manager) Stop() {
-	if m.ticker == nil {
+	if m.stop == nil {
 		return // Not started
 	}
 
 	logger := util.GetLogger()
 	logger.Info("Stopping game tick loop")
 
-	m.ticker.Stop()
-	m.ticker = nil
+	close(m.stop)
+	m.stop = nil
+	m.closeEditQueue()
 }
 
 func NewManager() *Manager {
 	manager := &Manager{
-		game:   gol.NewGame(),
-		stats:  GameStats{},
-		ticker: nil,
+		game:        gol.NewGame(),
+		stats:       GameStats{},
+		topology:    Topology{Kind: TopologyInfinite},
+		stop:        nil,
+		intervals:   make(chan time.Duration, 1),
+		scheduler:   tickScheduler{policy: TickPolicySkip},
+		tickWorkers: DefaultTickWorkers(),
+		extents:     newExtents(),
+		period:      NewPeriodDetector(DefaultPeriodWindow),
+		history:     NewStatsHistory(DefaultHistorySize),
+		observers:   make(map[grid.Observer]struct{}),
+		schedule:    make(map[string]ScheduledEdit),
+		zones:       make(map[string]Zone),
+		journals:    make(map[string]*Journal),
 	}
 	return manager
 }
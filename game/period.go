@@ -0,0 +1,163 @@
+package game
+
+import (
+	"github.com/henilmalaviya/gol/grid"
+	"github.com/henilmalaviya/golw/util"
+)
+
+// The world is periodic once a state repeats. States are compared by a hash
+// that is the sum of a per-cell hash, so it can be updated from the born and
+// died cells of a tick without walking the whole grid.
+
+// cellHash mixes a cell's coordinates with the splitmix64 finalizer.
+func cellHash(c grid.Cell) uint64 {
+	h := uint64(uint32(c.X))<<32 | uint64(uint32(c.Y))
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
+
+// StateHash returns the hash of a set of live cells.
+func StateHash(cells []grid.Cell) uint64 {
+	var h uint64
+	for _, c := range cells {
+		h += cellHash(c)
+	}
+	return h
+}
+
+// updateStateHash applies the changes of a tick to a state hash.
+func updateStateHash(h uint64, born, died []grid.Cell) uint64 {
+	return h + StateHash(born) - StateHash(died)
+}
+
+// PeriodEvent reports that the world, or an observer's region when Region is
+// set, became periodic or stopped being periodic. Period is 1 for a still
+// life and 0 when the state is no longer periodic.
+type PeriodEvent struct {
+	Generation   int             `json:"generation"`
+	Period       int             `json:"period"`
+	StabilizedAt int             `json:"stabilized_at"`
+	Region       *grid.Rectangle `json:"-"`
+}
+
+func (e PeriodEvent) Type() grid.ObserverEventType {
+	return "periodic"
+}
+
+// DefaultPeriodWindow is the longest period the manager detects unless
+// configured otherwise.
+const DefaultPeriodWindow = 64
+
+type periodSample struct {
+	generation int
+	hash       uint64
+}
+
+// PeriodDetector finds repeating states among the last window generations.
+type PeriodDetector struct {
+	window  int
+	samples []periodSample // ring buffer, oldest at next once full
+	next    int
+	seen    map[uint64]int // hash to the latest generation it was seen at
+
+	Period       int
+	StabilizedAt int
+}
+
+// NewPeriodDetector returns a detector for periods up to window generations.
+// A window of zero disables detection.
+func NewPeriodDetector(window int) *PeriodDetector {
+	return &PeriodDetector{
+		window: window,
+		seen:   make(map[uint64]int, window),
+	}
+}
+
+// Reset forgets the recorded states but keeps the last known period, so a
+// state restored from a snapshot is not reported as changed when it repeats
+// again.
+func (d *PeriodDetector) Reset() {
+	d.samples = d.samples[:0]
+	d.next = 0
+	clear(d.seen)
+}
+
+// Observe records the state hash of a generation and reports whether Period
+// or StabilizedAt changed.
+func (d *PeriodDetector) Observe(generation int, hash uint64) bool {
+	if d.window <= 0 {
+		return false
+	}
+
+	period, stabilizedAt := d.Period, d.StabilizedAt
+	if prev, ok := d.seen[hash]; ok && prev < generation {
+		if p := generation - prev; p != d.Period {
+			period, stabilizedAt = p, prev
+		}
+	} else if d.Period > 0 && len(d.samples) >= d.Period {
+		// A periodic state would have matched one recorded Period
+		// generations ago.
+		period, stabilizedAt = 0, 0
+	}
+
+	d.record(generation, hash)
+
+	if period == d.Period && stabilizedAt == d.StabilizedAt {
+		return false
+	}
+	d.Period, d.StabilizedAt = period, stabilizedAt
+	return true
+}
+
+func (d *PeriodDetector) record(generation int, hash uint64) {
+	sample := periodSample{generation: generation, hash: hash}
+
+	if len(d.samples) < d.window {
+		d.samples = append(d.samples, sample)
+	} else {
+		old := d.samples[d.next]
+		if d.seen[old.hash] == old.generation {
+			delete(d.seen, old.hash)
+		}
+		d.samples[d.next] = sample
+		d.next = (d.next + 1) % d.window
+	}
+	d.seen[hash] = generation
+}
+
+// SetPeriodWindow sets the longest period the manager detects. Zero
+// disables detection.
+func (m *Manager) SetPeriodWindow(window int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.period = NewPeriodDetector(max(window, 0))
+	m.stats.Period, m.stats.StabilizedAt = 0, 0
+}
+
+// detectPeriodLocked records the current state and notifies observers when
+// the world's period changes. The caller must hold m.mutex.
+func (m *Manager) detectPeriodLocked() {
+	if !m.period.Observe(m.stats.Generation, m.stateHash) {
+		return
+	}
+
+	m.stats.Period = m.period.Period
+	m.stats.StabilizedAt = m.period.StabilizedAt
+
+	if m.period.Period > 0 {
+		util.GetLogger().Info("World became periodic", "period", m.period.Period, "stabilized_at", m.period.StabilizedAt)
+	} else {
+		util.GetLogger().Info("World is no longer periodic", "generation", m.stats.Generation)
+	}
+
+	m.notifyObservers(PeriodEvent{
+		Generation:   m.stats.Generation,
+		Period:       m.period.Period,
+		StabilizedAt: m.period.StabilizedAt,
+	})
+}
@@ -0,0 +1,183 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/henilmalaviya/gol/grid"
+	"github.com/henilmalaviya/golw/util"
+)
+
+// PopulationAction is what the manager does when the live cell count
+// reaches the hard cap.
+type PopulationAction string
+
+const (
+	// PopulationActionPause pauses the tick loop.
+	PopulationActionPause PopulationAction = "pause"
+	// PopulationActionReject keeps ticking but rejects edits that add cells.
+	PopulationActionReject PopulationAction = "reject"
+	// PopulationActionCull removes live cells outside the cull bounds.
+	PopulationActionCull PopulationAction = "cull"
+)
+
+var ErrPopulationCap = errors.New("population cap reached")
+
+func ParsePopulationAction(action string) (PopulationAction, error) {
+	switch PopulationAction(action) {
+	case PopulationActionPause, PopulationActionReject, PopulationActionCull:
+		return PopulationAction(action), nil
+	default:
+		return "", fmt.Errorf("unknown population action %q", action)
+	}
+}
+
+// PopulationLimits caps the number of live cells. A cap of zero disables it.
+// Crossing the soft cap only raises an alert, crossing the hard cap also
+// triggers Action.
+type PopulationLimits struct {
+	SoftCap    int
+	HardCap    int
+	Action     PopulationAction
+	CullBounds grid.Rectangle
+}
+
+type AlertLevel string
+
+const (
+	AlertLevelNone AlertLevel = ""
+	AlertLevelSoft AlertLevel = "soft"
+	AlertLevelHard AlertLevel = "hard"
+)
+
+// PopulationAlertEvent is broadcast when the population crosses a cap.
+type PopulationAlertEvent struct {
+	Level      AlertLevel       `json:"level"`
+	Population int              `json:"population"`
+	Cap        int              `json:"cap"`
+	Action     PopulationAction `json:"action,omitempty"`
+	Generation int              `json:"generation"`
+}
+
+const PopulationAlertEventType grid.ObserverEventType = "population_alert"
+
+func (e PopulationAlertEvent) Type() grid.ObserverEventType {
+	return PopulationAlertEventType
+}
+
+func (l PopulationLimits) levelFor(population int) AlertLevel {
+	if l.HardCap > 0 && population >= l.HardCap {
+		return AlertLevelHard
+	}
+	if l.SoftCap > 0 && population >= l.SoftCap {
+		return AlertLevelSoft
+	}
+	return AlertLevelNone
+}
+
+func (m *Manager) SetPopulationLimits(limits PopulationLimits) {
+	limits.CullBounds = *limits.CullBounds.Normalized()
+
+	m.mutex.Lock()
+	m.limits = limits
+	m.mutex.Unlock()
+}
+
+func (m *Manager) GetPopulationLimits() PopulationLimits {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.limits
+}
+
+// checkPopulationLocked returns ErrPopulationCap if applying the edits
+// would grow the population past the hard cap under the reject action.
+// Edits are counted against the current grid, so it must run in the same
+// critical section that applies them. The caller must hold m.mutex.
+func (m *Manager) checkPopulationLocked(edits []CellEdit) error {
+	if m.limits.HardCap <= 0 || m.limits.Action != PopulationActionReject {
+		return nil
+	}
+
+	gr := m.game.GetGrid()
+
+	// Later edits of a cell win, as when they are applied in order.
+	final := make(map[grid.Cell]bool, len(edits))
+	for _, e := range edits {
+		if m.topology.Contains(e.Cell.X, e.Cell.Y) {
+			final[e.Cell] = e.Alive
+		}
+	}
+
+	growth := 0
+	for c, alive := range final {
+		switch was := gr.IsAlive(c.X, c.Y); {
+		case alive && !was:
+			growth++
+		case !alive && was:
+			growth--
+		}
+	}
+
+	population := gr.Population()
+	if growth > 0 && population+growth > m.limits.HardCap {
+		return fmt.Errorf("%w: %d live cells, cap is %d", ErrPopulationCap, population, m.limits.HardCap)
+	}
+	return nil
+}
+
+// cull removes live cells outside the cull bounds and returns them.
+// The caller must hold m.mutex.
+func (m *Manager) cull() []grid.Cell {
+	gr := m.game.GetGrid()
+	bounds := m.limits.CullBounds
+
+	var culled []grid.Cell
+	for _, c := range gr.GetCells() {
+		if !c.Inside(&bounds) {
+			gr.ClearCell(c.X, c.Y)
+			culled = append(culled, c)
+		}
+	}
+	return culled
+}
+
+// enforcePopulationLimits applies the hard cap action and raises alerts when
+// the population moves into a higher level. It returns any culled cells.
+// The caller must hold m.mutex.
+func (m *Manager) enforcePopulationLimits() []grid.Cell {
+	population := m.game.GetGrid().Population()
+	level := m.limits.levelFor(population)
+
+	var culled []grid.Cell
+	if level == AlertLevelHard {
+		switch m.limits.Action {
+		case PopulationActionPause:
+			m.paused = true
+		case PopulationActionCull:
+			culled = m.cull()
+			m.stats.CulledCount += len(culled)
+		}
+	}
+
+	previous := m.alertLevel
+	m.alertLevel = level
+	if level == AlertLevelNone || level == previous || (level == AlertLevelSoft && previous == AlertLevelHard) {
+		return culled
+	}
+
+	event := PopulationAlertEvent{
+		Level:      level,
+		Population: population,
+		Cap:        m.limits.SoftCap,
+		Generation: m.stats.Generation,
+	}
+	if level == AlertLevelHard {
+		event.Cap = m.limits.HardCap
+		event.Action = m.limits.Action
+	}
+
+	util.GetLogger().Warn("Population cap reached", "level", level, "population", population, "cap", event.Cap, "action", event.Action)
+	go m.notifyObservers(event)
+
+	return culled
+}
@@ -0,0 +1,98 @@
+package game
+
+import (
+	"sync"
+)
+
+// EditResult reports the outcome of a queued edit. Generation is the
+// generation whose state first includes the edit.
+type EditResult struct {
+	Generation int
+	Changes    []CellChange
+}
+
+type pendingEdit struct {
+	apply func() ([]CellChange, error)
+	done  chan editOutcome
+}
+
+type editOutcome struct {
+	result EditResult
+	err    error
+}
+
+// editQueue holds edits until the tick loop applies them between two ticks.
+// While the loop is not running edits are applied straight away.
+type editQueue struct {
+	open    bool
+	pending []pendingEdit
+
+	mutex sync.Mutex
+}
+
+// submit runs apply with m.mutex held at the next tick boundary and waits
+// for it to finish. apply checks the edit against the state it is applied
+// to and returns an error, without changing anything, to reject it.
+func (m *Manager) submit(apply func() ([]CellChange, error)) (EditResult, error) {
+	m.edits.mutex.Lock()
+	if !m.edits.open {
+		m.edits.mutex.Unlock()
+
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+		outcome := m.runEditLocked(apply)
+		return outcome.result, outcome.err
+	}
+
+	done := make(chan editOutcome, 1)
+	m.edits.pending = append(m.edits.pending, pendingEdit{apply: apply, done: done})
+	m.edits.mutex.Unlock()
+
+	outcome := <-done
+	return outcome.result, outcome.err
+}
+
+// runEditLocked runs a submitted edit. The caller must hold m.mutex.
+func (m *Manager) runEditLocked(apply func() ([]CellChange, error)) editOutcome {
+	changes, err := apply()
+	return editOutcome{result: EditResult{Generation: m.stats.Generation, Changes: changes}, err: err}
+}
+
+// applyPendingEdits applies every queued edit in the order it was submitted.
+func (m *Manager) applyPendingEdits() {
+	m.edits.mutex.Lock()
+	pending := m.edits.pending
+	m.edits.pending = nil
+	m.edits.mutex.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	m.mutex.Lock()
+	outcomes := make([]editOutcome, len(pending))
+	for i, p := range pending {
+		outcomes[i] = m.runEditLocked(p.apply)
+	}
+	m.mutex.Unlock()
+
+	for i, p := range pending {
+		p.done <- outcomes[i]
+	}
+}
+
+func (m *Manager) openEditQueue() {
+	m.edits.mutex.Lock()
+	m.edits.open = true
+	m.edits.mutex.Unlock()
+}
+
+// closeEditQueue makes new edits apply immediately and flushes the ones
+// still waiting for a tick.
+func (m *Manager) closeEditQueue() {
+	m.edits.mutex.Lock()
+	m.edits.open = false
+	m.edits.mutex.Unlock()
+
+	m.applyPendingEdits()
+}
@@ -0,0 +1,159 @@
+package game
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/henilmalaviya/gol/grid"
+)
+
+// RLE is the run length encoded pattern format used by most Life software.
+// A pattern is a header line "x = <width>, y = <height>, rule = B3/S23"
+// followed by runs of dead (b) and live (o) cells, with $ ending a row and !
+// ending the pattern. Lines starting with # are comments; "#R x y" gives the
+// position of the pattern's top left corner.
+
+// rleLineLength is the longest line EncodeRLE writes, as the format asks.
+const rleLineLength = 70
+
+// EncodeRLE writes cells as an RLE pattern, placed with an #R line and
+// preceded by the given comments.
+func EncodeRLE(w io.Writer, cells []grid.Cell, comments ...string) error {
+	norm, origin := normalize(cells)
+	width, height := 0, 0
+	for _, c := range norm {
+		width, height = max(width, c.X+1), max(height, c.Y+1)
+	}
+
+	bw := bufio.NewWriter(w)
+	for _, comment := range comments {
+		fmt.Fprintf(bw, "#C %s\n", comment)
+	}
+	fmt.Fprintf(bw, "#R %d %d\n", origin.X, origin.Y)
+	fmt.Fprintf(bw, "x = %d, y = %d, rule = B3/S23\n", width, height)
+
+	line := 0
+	emit := func(count int, tag byte) {
+		token := string(tag)
+		if count > 1 {
+			token = strconv.Itoa(count) + token
+		}
+		if line+len(token) > rleLineLength {
+			bw.WriteByte('\n')
+			line = 0
+		}
+		bw.WriteString(token)
+		line += len(token)
+	}
+
+	// norm is sorted by row, then column, so runs can be read off in order.
+	x, y := 0, 0
+	for i := 0; i < len(norm); {
+		c := norm[i]
+		if c.Y > y {
+			emit(c.Y-y, '$')
+			x, y = 0, c.Y
+		}
+		if c.X > x {
+			emit(c.X-x, 'b')
+		}
+
+		run := 1
+		for i+run < len(norm) && norm[i+run].Y == c.Y && norm[i+run].X == c.X+run {
+			run++
+		}
+		emit(run, 'o')
+		x = c.X + run
+		i += run
+	}
+	emit(1, '!')
+	bw.WriteByte('\n')
+
+	return bw.Flush()
+}
+
+// DecodeRLE reads an RLE pattern. The cells are placed at the pattern's #R
+// position when it has one, and at the origin otherwise. Patterns for rules
+// other than B3/S23 are rejected.
+func DecodeRLE(r io.Reader) ([]grid.Cell, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+
+	var originX, originY int
+	var body strings.Builder
+	headerSeen := false
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "#"):
+			if fields := strings.Fields(line); len(fields) == 3 && (fields[0] == "#R" || fields[0] == "#P") {
+				x, errX := strconv.Atoi(fields[1])
+				y, errY := strconv.Atoi(fields[2])
+				if errX != nil || errY != nil {
+					return nil, fmt.Errorf("invalid position line %q", line)
+				}
+				originX, originY = x, y
+			}
+		case !headerSeen && body.Len() == 0 && strings.HasPrefix(line, "x"):
+			if err := checkRLEHeader(line); err != nil {
+				return nil, err
+			}
+			headerSeen = true
+		default:
+			body.WriteString(line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var cells []grid.Cell
+	x, y, count := 0, 0, 0
+	for _, ch := range body.String() {
+		switch {
+		case ch >= '0' && ch <= '9':
+			count = count*10 + int(ch-'0')
+			continue
+		case ch == 'b' || ch == '.':
+			x += max(count, 1)
+		case ch == 'o' || (ch >= 'A' && ch <= 'X'):
+			for range max(count, 1) {
+				cells = append(cells, grid.Cell{X: originX + x, Y: originY + y})
+				x++
+			}
+		case ch == '$':
+			x, y = 0, y+max(count, 1)
+		case ch == '!':
+			return cells, nil
+		case ch == ' ' || ch == '\t':
+		default:
+			return nil, fmt.Errorf("unexpected %q in pattern", ch)
+		}
+		count = 0
+	}
+	return nil, fmt.Errorf("pattern does not end with !")
+}
+
+// checkRLEHeader checks the rule named in an RLE header line.
+func checkRLEHeader(line string) error {
+	for _, part := range strings.Split(line, ",") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return fmt.Errorf("invalid header %q", line)
+		}
+		if strings.TrimSpace(key) != "rule" {
+			continue
+		}
+
+		switch rule := strings.ToUpper(strings.TrimSpace(value)); rule {
+		case "B3/S23", "23/3":
+		default:
+			return fmt.Errorf("unsupported rule %s, only B3/S23 is supported", rule)
+		}
+	}
+	return nil
+}
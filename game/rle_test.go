@@ -0,0 +1,146 @@
+package game
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/henilmalaviya/gol/grid"
+)
+
+func TestRLERoundTrip(t *testing.T) {
+	moved, _, _ := Transform(picture("OO./O.O/.O."), -20, -30, 0, FlipNone)
+
+	long := make([]grid.Cell, 0, 100)
+	for x := 0; x < 200; x += 2 {
+		long = append(long, grid.Cell{X: x, Y: 0})
+	}
+
+	tests := []struct {
+		name  string
+		cells []grid.Cell
+	}{
+		{name: "glider", cells: picture(".O./..O/OOO")},
+		{name: "negative position", cells: moved},
+		{name: "blank rows", cells: []grid.Cell{{X: 0, Y: 0}, {X: 3, Y: 4}, {X: 1, Y: 9}}},
+		{name: "wrapped lines", cells: long},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := EncodeRLE(&buf, tt.cells, "a comment"); err != nil {
+				t.Fatal(err)
+			}
+			for _, line := range strings.Split(buf.String(), "\n") {
+				if len(line) > rleLineLength {
+					t.Errorf("line of %d characters: %q", len(line), line)
+				}
+			}
+
+			got, err := DecodeRLE(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			gotCells, gotOrigin := normalize(got)
+			wantCells, wantOrigin := normalize(tt.cells)
+			if !reflect.DeepEqual(gotCells, wantCells) || gotOrigin != wantOrigin {
+				t.Errorf("got %v, want %v", got, tt.cells)
+			}
+		})
+	}
+}
+
+func TestDecodeRLE(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []grid.Cell
+		wantErr bool
+	}{
+		{
+			name:  "run counts",
+			input: "x = 6, y = 1\n2b3ob!",
+			want:  []grid.Cell{{X: 2, Y: 0}, {X: 3, Y: 0}, {X: 4, Y: 0}},
+		},
+		{
+			name:  "multi digit count",
+			input: "12o!",
+			want:  picture("OOOOOOOOOOOO"),
+		},
+		{
+			name:  "row runs",
+			input: "o3$bo!",
+			want:  []grid.Cell{{X: 0, Y: 0}, {X: 1, Y: 3}},
+		},
+		{
+			name:  "position and comments",
+			input: "#C a glider\n#R -5 7\nx = 1, y = 1\no!",
+			want:  []grid.Cell{{X: -5, Y: 7}},
+		},
+		{
+			name:  "body over several lines",
+			input: "x = 3, y = 2\nbo\n$3o!",
+			want:  []grid.Cell{{X: 1, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}, {X: 2, Y: 1}},
+		},
+		{
+			name:  "lower case rule",
+			input: "x = 1, y = 1, rule = b3/s23\no!",
+			want:  []grid.Cell{{X: 0, Y: 0}},
+		},
+		{
+			name:  "old style rule",
+			input: "x = 1, y = 1, rule = 23/3\no!",
+			want:  []grid.Cell{{X: 0, Y: 0}},
+		},
+		{
+			name:    "other rule",
+			input:   "x = 1, y = 1, rule = B36/S23\no!",
+			wantErr: true,
+		},
+		{
+			name:    "rule with a bounded grid suffix",
+			input:   "x = 1, y = 1, rule = B3/S23:T10,10\no!",
+			wantErr: true,
+		},
+		{
+			name:    "invalid header",
+			input:   "x 1, y = 1\no!",
+			wantErr: true,
+		},
+		{
+			name:    "invalid position",
+			input:   "#R a b\no!",
+			wantErr: true,
+		},
+		{
+			name:    "missing end",
+			input:   "x = 2, y = 1\n2o",
+			wantErr: true,
+		},
+		{
+			name:    "unexpected character",
+			input:   "2oz!",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeRLE(strings.NewReader(tt.input))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("got %v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -6,15 +6,79 @@ import (
 	"time"
 
 	"github.com/henilmalaviya/filic"
+	"github.com/henilmalaviya/gol/grid"
 	"github.com/henilmalaviya/golw/env"
 	"github.com/henilmalaviya/golw/util"
 )
 
+// SnapshotVersion is the version written to new snapshots. Version 2 added
+// protected zones and version 3 scheduled edits.
+const SnapshotVersion = 3
+
 type Snapshot struct {
-	Version   int       `json:"version"`
-	Timestamp time.Time `json:"timestamp"`
-	Stats     GameStats `json:"stats"`
-	Grid      [][2]int  `json:"grid"`
+	Version   int             `json:"version"`
+	Timestamp time.Time       `json:"timestamp"`
+	Stats     GameStats       `json:"stats"`
+	Grid      [][2]int        `json:"grid"`
+	Zones     []Zone          `json:"zones,omitempty"`
+	Schedule  []ScheduledEdit `json:"schedule,omitempty"`
+}
+
+// Cells returns the snapshot's live cells.
+func (snap *Snapshot) Cells() []grid.Cell {
+	cells := make([]grid.Cell, len(snap.Grid))
+	for i, coord := range snap.Grid {
+		cells[i] = grid.Cell{X: coord[0], Y: coord[1]}
+	}
+	return cells
+}
+
+// SnapshotFileName returns the name of the file a snapshot taken at t is
+// saved to.
+func SnapshotFileName(t time.Time) string {
+	return "save_" + t.Format("20060102_150405") + ".json"
+}
+
+func ReadSnapshot(path string) (*Snapshot, error) {
+	var snap Snapshot
+	if err := util.ReadJSONFromFile(path, &snap); err != nil {
+		return nil, err
+	}
+	return &snap, nil
+}
+
+func WriteSnapshot(path string, snap *Snapshot) error {
+	return util.WriteJSONToFile(path, snap)
+}
+
+// SnapshotFile is a file in a save directory.
+type SnapshotFile struct {
+	Path    string
+	ModTime time.Time
+	Size    int64
+}
+
+// ListSnapshotFiles returns the files in a save directory, newest first.
+// Files that cannot be stat'ed are left out.
+func ListSnapshotFiles(dir string) ([]SnapshotFile, error) {
+	files, err := filic.NewDirectory(dir).ListFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []SnapshotFile
+	for _, f := range files {
+		info, err := os.Stat(f.Path)
+		if err != nil {
+			continue
+		}
+		out = append(out, SnapshotFile{Path: f.Path, ModTime: info.ModTime(), Size: info.Size()})
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].ModTime.After(out[j].ModTime)
+	})
+	return out, nil
 }
 
 /* -------------------------------------------------------------------------- */
@@ -39,17 +103,26 @@ func NewSaveManager(manager *Manager) *GameSaver {
 	}
 }
 
-func (s *GameSaver) Snapshot() *Snapshot {
-	game := s.manager.GetGame()
+// Snapshot captures the game in one hold of the manager's lock, so the
+// stats, grid and schedule all describe the same generation.
+func (m *Manager) Snapshot() *Snapshot {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 
 	return &Snapshot{
-		Version:   1,
+		Version:   SnapshotVersion,
 		Timestamp: time.Now(),
-		Stats:     s.manager.GetStats(),
-		Grid:      game.GetGrid().GetLiveCellCoordinates(),
+		Stats:     m.statsLocked(),
+		Grid:      m.game.GetGrid().GetLiveCellCoordinates(),
+		Zones:     m.Zones(),
+		Schedule:  m.scheduledEditsLocked(),
 	}
 }
 
+func (s *GameSaver) Snapshot() *Snapshot {
+	return s.manager.Snapshot()
+}
+
 func (s *GameSaver) StartSaving() {
 	if s.SaveInterval <= 0 {
 		return // No saving needed
@@ -60,9 +133,9 @@ func (s *GameSaver) StartSaving() {
 		for range s.ticker.C {
 
 			snap := s.Snapshot()
-			filePath := s.SaveDir + "/save_" + snap.Timestamp.Format("20060102_150405") + ".json"
+			filePath := s.SaveDir + "/" + SnapshotFileName(snap.Timestamp)
 
-			if err := util.WriteJSONToFile(filePath, snap); err != nil {
+			if err := WriteSnapshot(filePath, snap); err != nil {
 				util.GetLogger().Error("Failed to save game state", "error", err)
 				return
 			}
@@ -82,46 +155,16 @@ func (s *GameSaver) StopSaving() {
 	}
 }
 
-func (s *GameSaver) getLatestSaveFile() (*filic.File, error) {
-	dir := filic.NewDirectory(s.SaveDir)
-
-	files, err := dir.ListFiles()
-	if err != nil {
-		return nil, err
-	}
-
-	if len(files) == 0 {
-		return nil, nil // No files found
-	}
-
-	// Sort files by modification time, newest first
-	sort.Slice(files, func(i, j int) bool {
-		infoI, errI := os.Stat(files[i].Path)
-		infoJ, errJ := os.Stat(files[j].Path)
-		if errI != nil || errJ != nil {
-			return false
-		}
-		return infoI.ModTime().After(infoJ.ModTime())
-	})
-
-	return files[0], nil
-}
-
 func (s *GameSaver) LoadLatestSnapshot() (*Snapshot, error) {
-	latestFile, err := s.getLatestSaveFile()
+	files, err := ListSnapshotFiles(s.SaveDir)
 	if err != nil {
 		return nil, err
 	}
-	if latestFile == nil {
+	if len(files) == 0 {
 		return nil, nil // No save file found
 	}
 
-	var snap Snapshot
-	if err := util.ReadJSONFromFile(latestFile.Path, &snap); err != nil {
-		return nil, err
-	}
-
-	return &snap, nil
+	return ReadSnapshot(files[0].Path)
 }
 
 func (s *GameSaver) LoadSnapshot(snap *Snapshot) {
@@ -134,9 +177,26 @@ func (s *GameSaver) LoadSnapshot(snap *Snapshot) {
 
 	s.manager.stats.LoadFromSnapshot(&snap.Stats)
 	s.manager.game.GetGrid().Clear()
+	skipped := 0
 	for _, coord := range snap.Grid {
+		if !s.manager.topology.Contains(coord[0], coord[1]) {
+			skipped++
+			continue
+		}
 		s.manager.game.GetGrid().SetCell(coord[0], coord[1])
 	}
+	if skipped > 0 {
+		util.GetLogger().Warn("Dropped snapshot cells outside the world bounds", "count", skipped)
+	}
+
+	s.manager.reindexLocked()
+	s.manager.history.Reset()
+	s.manager.period.Reset()
+	s.manager.period.Period = snap.Stats.Period
+	s.manager.period.StabilizedAt = snap.Stats.StabilizedAt
+
+	s.manager.loadZones(snap.Zones)
+	s.manager.loadScheduleLocked(snap.Schedule)
 
 	util.GetLogger().Info("Game state loaded from snapshot", "timestamp", snap.Timestamp, "generation", snap.Stats.Generation)
 }
@@ -0,0 +1,238 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/henilmalaviya/gol/grid"
+	"github.com/henilmalaviya/golw/util"
+)
+
+var (
+	ErrScheduledEditNotFound = errors.New("scheduled edit not found")
+	ErrGenerationPassed      = errors.New("generation has already passed")
+)
+
+// ScheduleMode is how a scheduled edit changes its cells.
+type ScheduleMode string
+
+const (
+	ScheduleModeSet    ScheduleMode = "set"
+	ScheduleModeClear  ScheduleMode = "clear"
+	ScheduleModeToggle ScheduleMode = "toggle"
+)
+
+func ParseScheduleMode(mode string) (ScheduleMode, error) {
+	switch ScheduleMode(mode) {
+	case ScheduleModeSet, ScheduleModeClear, ScheduleModeToggle:
+		return ScheduleMode(mode), nil
+	default:
+		return "", fmt.Errorf("unknown schedule mode %q", mode)
+	}
+}
+
+// ScheduledEdit is an edit that is applied right after the tick that
+// produces Generation, so the state of that generation includes it. Admin
+// edits may change protected zones.
+type ScheduledEdit struct {
+	ID         string
+	Generation int
+	Cells      []grid.Cell
+	Mode       ScheduleMode
+	Author     Author
+	Admin      bool
+	CreatedAt  time.Time
+}
+
+type scheduledEditJSON struct {
+	ID         string       `json:"id"`
+	Generation int          `json:"generation"`
+	Cells      [][2]int     `json:"cells"`
+	Mode       ScheduleMode `json:"mode"`
+	Author     Author       `json:"author"`
+	Admin      bool         `json:"admin,omitempty"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+func (e ScheduledEdit) MarshalJSON() ([]byte, error) {
+	cells := make([][2]int, len(e.Cells))
+	for i, c := range e.Cells {
+		cells[i] = [2]int{c.X, c.Y}
+	}
+
+	return json.Marshal(scheduledEditJSON{
+		ID:         e.ID,
+		Generation: e.Generation,
+		Cells:      cells,
+		Mode:       e.Mode,
+		Author:     e.Author,
+		Admin:      e.Admin,
+		CreatedAt:  e.CreatedAt,
+	})
+}
+
+func (e *ScheduledEdit) UnmarshalJSON(data []byte) error {
+	var raw scheduledEditJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	cells := make([]grid.Cell, len(raw.Cells))
+	for i, c := range raw.Cells {
+		cells[i] = grid.Cell{X: c[0], Y: c[1]}
+	}
+
+	*e = ScheduledEdit{
+		ID:         raw.ID,
+		Generation: raw.Generation,
+		Cells:      cells,
+		Mode:       raw.Mode,
+		Author:     raw.Author,
+		Admin:      raw.Admin,
+		CreatedAt:  raw.CreatedAt,
+	}
+	return nil
+}
+
+// ScheduleEdit stores an edit to run at a future generation and returns it
+// with its id and creation time set.
+func (m *Manager) ScheduleEdit(edit ScheduledEdit) (ScheduledEdit, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if edit.Generation <= m.stats.Generation {
+		return ScheduledEdit{}, fmt.Errorf("%w: current generation is %d", ErrGenerationPassed, m.stats.Generation)
+	}
+
+	edit.ID = newID("e")
+	edit.CreatedAt = time.Now()
+	m.schedule[edit.ID] = edit
+
+	return edit, nil
+}
+
+// CancelScheduledEdit removes a pending edit. Unless force is set, only the
+// author who scheduled it may cancel it: the same verified user, or the same
+// connection for authors without one.
+func (m *Manager) CancelScheduledEdit(id string, author Author, force bool) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	edit, ok := m.schedule[id]
+	if !ok || (!force && edit.Author.key() != author.key()) {
+		return fmt.Errorf("%w: %s", ErrScheduledEditNotFound, id)
+	}
+
+	delete(m.schedule, id)
+	return nil
+}
+
+// ScheduledEdits returns the pending edits ordered by generation.
+func (m *Manager) ScheduledEdits() []ScheduledEdit {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.scheduledEditsLocked()
+}
+
+// CountScheduledEdits returns how many edits the author has pending.
+func (m *Manager) CountScheduledEdits(author Author) int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	count := 0
+	for _, e := range m.schedule {
+		if e.Author.key() == author.key() {
+			count++
+		}
+	}
+	return count
+}
+
+func (m *Manager) scheduledEditsLocked() []ScheduledEdit {
+	edits := make([]ScheduledEdit, 0, len(m.schedule))
+	for _, e := range m.schedule {
+		edits = append(edits, e)
+	}
+
+	sort.Slice(edits, func(i, j int) bool {
+		if edits[i].Generation != edits[j].Generation {
+			return edits[i].Generation < edits[j].Generation
+		}
+		if !edits[i].CreatedAt.Equal(edits[j].CreatedAt) {
+			return edits[i].CreatedAt.Before(edits[j].CreatedAt)
+		}
+		return edits[i].ID < edits[j].ID
+	})
+	return edits
+}
+
+// runScheduledEditsLocked applies the edits due at the current generation,
+// oldest first. Edits whose generation was skipped, for example by loading an
+// older snapshot, are dropped, as are edits that a protected zone or the
+// population cap rejects now that they run. The caller must hold m.mutex.
+func (m *Manager) runScheduledEditsLocked() {
+	if len(m.schedule) == 0 {
+		return
+	}
+
+	logger := util.GetLogger()
+	gr := m.game.GetGrid()
+
+	for _, e := range m.scheduledEditsLocked() {
+		if e.Generation > m.stats.Generation {
+			break
+		}
+		delete(m.schedule, e.ID)
+
+		if e.Generation < m.stats.Generation {
+			logger.Warn("Dropped scheduled edit for a past generation", "id", e.ID, "generation", e.Generation)
+			continue
+		}
+
+		edits := make([]CellEdit, 0, len(e.Cells))
+		seen := make(map[grid.Cell]struct{}, len(e.Cells))
+		for _, c := range e.Cells {
+			if _, ok := seen[c]; ok {
+				continue
+			}
+			seen[c] = struct{}{}
+
+			alive := e.Mode != ScheduleModeClear
+			if e.Mode == ScheduleModeToggle {
+				alive = !gr.IsAlive(c.X, c.Y)
+			}
+			edits = append(edits, CellEdit{Cell: c, Alive: alive})
+		}
+
+		if err := m.checkScheduledEditLocked(e, edits); err != nil {
+			logger.Warn("Dropped scheduled edit", "id", e.ID, "generation", e.Generation, "error", err)
+			continue
+		}
+
+		changes := m.setCellsLocked(edits, e.Author)
+		logger.Info("Applied scheduled edit", "id", e.ID, "generation", e.Generation, "changed", len(changes))
+	}
+}
+
+// checkScheduledEditLocked checks a due edit against the zones and the
+// population as they are when it runs. The caller must hold m.mutex.
+func (m *Manager) checkScheduledEditLocked(e ScheduledEdit, edits []CellEdit) error {
+	if !e.Admin {
+		if err := m.CheckZones(e.Cells, e.Author); err != nil {
+			return err
+		}
+	}
+	return m.checkPopulationLocked(edits)
+}
+
+// loadScheduleLocked replaces the pending edits with the ones from a snapshot.
+// The caller must hold m.mutex.
+func (m *Manager) loadScheduleLocked(edits []ScheduledEdit) {
+	clear(m.schedule)
+	for _, e := range edits {
+		m.schedule[e.ID] = e
+	}
+}
@@ -0,0 +1,205 @@
+package game
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/henilmalaviya/golw/util"
+)
+
+// TickPolicy decides what the tick loop does when a tick takes longer than
+// the tick interval.
+type TickPolicy string
+
+const (
+	// TickPolicySkip drops the ticks that were missed and waits for the
+	// next slot on the original schedule.
+	TickPolicySkip TickPolicy = "skip"
+	// TickPolicyCatchUp runs missed ticks back to back, up to the manager's
+	// catch-up limit, before returning to the schedule.
+	TickPolicyCatchUp TickPolicy = "catchup"
+	// TickPolicySlow stretches the interval to fit the tick duration and
+	// shrinks it back towards the configured interval once ticks are fast
+	// again.
+	TickPolicySlow TickPolicy = "slow"
+)
+
+func ParseTickPolicy(policy string) (TickPolicy, error) {
+	switch TickPolicy(policy) {
+	case TickPolicySkip, TickPolicyCatchUp, TickPolicySlow:
+		return TickPolicy(policy), nil
+	default:
+		return "", fmt.Errorf("unknown tick policy %q", policy)
+	}
+}
+
+// TickStats describes how the tick loop is keeping up with its schedule.
+type TickStats struct {
+	TickRate       float64 `json:"tick_rate"`
+	TickIntervalMs float64 `json:"tick_interval_ms"`
+	LastTickMs     float64 `json:"last_tick_ms"`
+	TickOverruns   int     `json:"tick_overruns"`
+	SkippedTicks   int     `json:"skipped_ticks"`
+}
+
+// tickRateSmoothing is the weight of the newest sample in the tick rate
+// moving average.
+const tickRateSmoothing = 0.2
+
+type tickScheduler struct {
+	policy       TickPolicy
+	maxCatchUp   int
+	baseInterval time.Duration
+	interval     time.Duration
+
+	next      time.Time
+	lastStart time.Time
+	overrun   int // length of the current overrun streak
+}
+
+func (m *Manager) recordTick(start time.Time, duration time.Duration, interval time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	ts := &m.stats.TickStats
+	ts.LastTickMs = float64(duration.Microseconds()) / 1000
+	ts.TickIntervalMs = float64(interval.Microseconds()) / 1000
+
+	if !m.scheduler.lastStart.IsZero() {
+		if elapsed := start.Sub(m.scheduler.lastStart); elapsed > 0 {
+			rate := float64(time.Second) / float64(elapsed)
+			if ts.TickRate == 0 {
+				ts.TickRate = rate
+			} else {
+				ts.TickRate += tickRateSmoothing * (rate - ts.TickRate)
+			}
+		}
+	}
+	m.scheduler.lastStart = start
+}
+
+// recordPause resets the tick rate while the loop is paused.
+func (m *Manager) recordPause() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.stats.TickRate = 0
+	m.scheduler.lastStart = time.Time{}
+}
+
+// scheduleNext works out when the next tick should run after a tick that
+// started at start and took duration.
+func (m *Manager) scheduleNext(start time.Time, duration time.Duration) {
+	logger := util.GetLogger()
+	s := &m.scheduler
+	now := start.Add(duration)
+
+	if duration > s.interval {
+		m.mutex.Lock()
+		m.stats.TickOverruns++
+		m.mutex.Unlock()
+
+		if s.overrun == 0 {
+			logger.Warn("Tick overran its interval", "duration", duration, "interval", s.interval, "policy", s.policy)
+		}
+		s.overrun++
+	} else if s.overrun > 0 {
+		logger.Info("Tick loop caught up", "overruns", s.overrun, "interval", s.interval)
+		s.overrun = 0
+	}
+
+	switch s.policy {
+	case TickPolicyCatchUp:
+		s.next = s.next.Add(s.interval)
+		if behind := now.Sub(s.next); behind > s.interval*time.Duration(s.maxCatchUp) {
+			missed := int(behind/s.interval) - s.maxCatchUp
+			m.addSkippedTicks(missed)
+			s.next = now
+		}
+	case TickPolicySlow:
+		if duration > s.interval {
+			s.interval = duration + duration/4
+		} else if s.interval > s.baseInterval && duration < s.interval/2 {
+			s.interval = max(s.baseInterval, s.interval-s.interval/10)
+		}
+		s.next = start.Add(s.interval)
+	default:
+		s.next = s.next.Add(s.interval)
+		if now.After(s.next) {
+			missed := int(now.Sub(s.next)/s.interval) + 1
+			m.addSkippedTicks(missed)
+			s.next = s.next.Add(s.interval * time.Duration(missed))
+		}
+	}
+}
+
+func (m *Manager) addSkippedTicks(count int) {
+	if count <= 0 {
+		return
+	}
+	m.mutex.Lock()
+	m.stats.SkippedTicks += count
+	m.mutex.Unlock()
+}
+
+func (m *Manager) runTickLoop(stop <-chan struct{}) {
+	s := &m.scheduler
+	s.next = time.Now().Add(s.interval)
+
+	timer := time.NewTimer(s.interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case interval := <-m.intervals:
+			s.baseInterval = interval
+			s.interval = interval
+			s.next = time.Now().Add(interval)
+			timer.Reset(interval)
+			continue
+		case <-timer.C:
+		}
+
+		m.applyPendingEdits()
+
+		if m.IsPaused() {
+			m.recordPause()
+			s.next = time.Now().Add(s.interval)
+			timer.Reset(s.interval)
+			continue
+		}
+
+		start := time.Now()
+		m.Tick()
+		duration := time.Since(start)
+
+		m.scheduleNext(start, duration)
+		m.recordTick(start, duration, s.interval)
+
+		timer.Reset(time.Until(s.next))
+	}
+}
+
+// SetTickInterval changes the tick interval of a running tick loop. The next
+// tick runs one new interval from now.
+func (m *Manager) SetTickInterval(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	// Only the latest change matters, so replace one the loop has not
+	// picked up yet.
+	for {
+		select {
+		case m.intervals <- interval:
+			return
+		default:
+		}
+		select {
+		case <-m.intervals:
+		default:
+		}
+	}
+}
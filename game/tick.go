@@ -0,0 +1,210 @@
+package game
+
+import (
+	"math/bits"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/henilmalaviya/gol/grid"
+)
+
+// The next generation is computed on fixed 64x64 chunks. Each chunk stores
+// one uint64 per row so a whole row can be evolved with bitwise operations.
+const (
+	chunkShift = 6
+	chunkSize  = 1 << chunkShift
+	chunkMask  = chunkSize - 1
+)
+
+type chunkKey struct {
+	X int
+	Y int
+}
+
+type chunk struct {
+	rows [chunkSize]uint64
+}
+
+func (c *chunk) row(y int) uint64 {
+	if c == nil {
+		return 0
+	}
+	return c.rows[y]
+}
+
+type chunkResult struct {
+	born []grid.Cell
+	died []grid.Cell
+}
+
+func chunkKeyOf(x, y int) chunkKey {
+	return chunkKey{X: x >> chunkShift, Y: y >> chunkShift}
+}
+
+// buildChunks partitions the live cells into chunks.
+func buildChunks(cells []grid.Cell) map[chunkKey]*chunk {
+	chunks := make(map[chunkKey]*chunk)
+
+	var lastKey chunkKey
+	var last *chunk
+	for _, c := range cells {
+		key := chunkKeyOf(c.X, c.Y)
+		if last == nil || key != lastKey {
+			ch, ok := chunks[key]
+			if !ok {
+				ch = &chunk{}
+				chunks[key] = ch
+			}
+			last, lastKey = ch, key
+		}
+		last.rows[c.Y&chunkMask] |= 1 << uint(c.X&chunkMask)
+	}
+
+	return chunks
+}
+
+// candidateChunks returns every chunk that may contain a live cell in the
+// next generation: the populated chunks and their neighbours, sorted by
+// row then column so results are merged in a stable order.
+func candidateChunks(chunks map[chunkKey]*chunk) []chunkKey {
+	seen := make(map[chunkKey]struct{}, len(chunks)*2)
+	for key := range chunks {
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				seen[chunkKey{X: key.X + dx, Y: key.Y + dy}] = struct{}{}
+			}
+		}
+	}
+
+	keys := make([]chunkKey, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Y != keys[j].Y {
+			return keys[i].Y < keys[j].Y
+		}
+		return keys[i].X < keys[j].X
+	})
+	return keys
+}
+
+// evolveChunk computes the born and died cells of a single chunk.
+func evolveChunk(chunks map[chunkKey]*chunk, key chunkKey) chunkResult {
+	var around [3][3]*chunk
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			around[dy+1][dx+1] = chunks[chunkKey{X: key.X + dx, Y: key.Y + dy}]
+		}
+	}
+
+	// line returns row y of the centre chunk together with its west and
+	// east neighbours shifted into place. y may be -1 or chunkSize, in which
+	// case the row is taken from the chunk above or below.
+	line := func(y int) (west, mid, east uint64) {
+		cy := 1
+		if y < 0 {
+			cy, y = 0, chunkMask
+		} else if y > chunkMask {
+			cy, y = 2, 0
+		}
+		mid = around[cy][1].row(y)
+		west = mid<<1 | around[cy][0].row(y)>>chunkMask
+		east = mid>>1 | around[cy][2].row(y)<<chunkMask
+		return west, mid, east
+	}
+
+	var result chunkResult
+	baseX, baseY := key.X<<chunkShift, key.Y<<chunkShift
+
+	for y := 0; y < chunkSize; y++ {
+		aw, a, ae := line(y - 1)
+		w, cur, e := line(y)
+		bw, b, be := line(y + 1)
+
+		// Bit-sliced neighbour count: s0 and s1 hold the count modulo 4
+		// and s2 is set once any lane reaches four.
+		var s0, s1, s2 uint64
+		for _, n := range [8]uint64{aw, a, ae, w, e, bw, b, be} {
+			c0 := s0 & n
+			s0 ^= n
+			c1 := s1 & c0
+			s1 ^= c0
+			s2 |= c1
+		}
+
+		next := s1 &^ s2 & (s0 | cur)
+
+		for born := next &^ cur; born != 0; born &= born - 1 {
+			x := bits.TrailingZeros64(born)
+			result.born = append(result.born, grid.Cell{X: baseX + x, Y: baseY + y})
+		}
+		for died := cur &^ next; died != 0; died &= died - 1 {
+			x := bits.TrailingZeros64(died)
+			result.died = append(result.died, grid.Cell{X: baseX + x, Y: baseY + y})
+		}
+	}
+
+	return result
+}
+
+// ComputeNextGeneration returns the cells that are born and that die when
+// advancing the given live cells by one generation.
+//
+// The live set is partitioned into chunks which are evolved across a pool of
+// workers. Results are merged in chunk order, so the output is identical for
+// any number of workers; workers <= 1 runs on the calling goroutine.
+func ComputeNextGeneration(cells []grid.Cell, workers int) ([]grid.Cell, []grid.Cell) {
+	chunks := buildChunks(cells)
+	keys := candidateChunks(chunks)
+	results := make([]chunkResult, len(keys))
+
+	if workers > len(keys) {
+		workers = len(keys)
+	}
+
+	if workers <= 1 {
+		for i, key := range keys {
+			results[i] = evolveChunk(chunks, key)
+		}
+	} else {
+		var next atomic.Int64
+		var wg sync.WaitGroup
+		for range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					i := int(next.Add(1) - 1)
+					if i >= len(keys) {
+						return
+					}
+					results[i] = evolveChunk(chunks, keys[i])
+				}
+			}()
+		}
+		wg.Wait()
+	}
+
+	var bornCount, diedCount int
+	for _, r := range results {
+		bornCount += len(r.born)
+		diedCount += len(r.died)
+	}
+
+	bornCells := make([]grid.Cell, 0, bornCount)
+	diedCells := make([]grid.Cell, 0, diedCount)
+	for _, r := range results {
+		bornCells = append(bornCells, r.born...)
+		diedCells = append(diedCells, r.died...)
+	}
+
+	return bornCells, diedCells
+}
+
+// DefaultTickWorkers returns the worker count used when none is configured.
+func DefaultTickWorkers() int {
+	return runtime.NumCPU()
+}
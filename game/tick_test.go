@@ -0,0 +1,201 @@
+package game
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/henilmalaviya/gol/grid"
+)
+
+var testWorkerCounts = []int{1, 2, 4, 8, 16}
+
+// soup returns the live cells of a random soup covering bounds.
+func soup(bounds grid.Rectangle, density float64, seed int64) []grid.Cell {
+	return RandomFill{Bounds: bounds, Density: density, Seed: seed}.Cells()
+}
+
+func sorted(cells []grid.Cell) []grid.Cell {
+	out := append([]grid.Cell(nil), cells...)
+	grid.SortCells(out)
+	return out
+}
+
+// referenceNext evolves cells cell by cell within the topology, without
+// chunks or ghost cells.
+func referenceNext(t Topology, cells []grid.Cell) ([]grid.Cell, []grid.Cell) {
+	if !t.IsBounded() {
+		g := grid.NewGrid()
+		for _, c := range cells {
+			g.SetCell(c.X, c.Y)
+		}
+		return g.ComputeNextGrid()
+	}
+
+	b := t.Bounds
+	alive := make(map[grid.Cell]bool, len(cells))
+	for _, c := range cells {
+		alive[c] = true
+	}
+
+	// at returns whether the cell at (x, y) is alive, following the wrapping
+	// of the topology.
+	at := func(x, y int) bool {
+		wx := b.X1 + ((x-b.X1)%b.Width()+b.Width())%b.Width()
+		wy := b.Y1 + ((y-b.Y1)%b.Height()+b.Height())%b.Height()
+		switch t.Kind {
+		case TopologyBounded:
+			if !b.PointInside(x, y) {
+				return false
+			}
+		case TopologyTorus:
+			x, y = wx, wy
+		case TopologyKlein:
+			if y != wy {
+				wx = b.X1 + b.X2 - wx
+			}
+			x, y = wx, wy
+		}
+		return alive[grid.Cell{X: x, Y: y}]
+	}
+
+	var born, died []grid.Cell
+	for y := b.Y1; y <= b.Y2; y++ {
+		for x := b.X1; x <= b.X2; x++ {
+			n := 0
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					if (dx != 0 || dy != 0) && at(x+dx, y+dy) {
+						n++
+					}
+				}
+			}
+
+			c := grid.Cell{X: x, Y: y}
+			switch {
+			case alive[c] && n != 2 && n != 3:
+				died = append(died, c)
+			case !alive[c] && n == 3:
+				born = append(born, c)
+			}
+		}
+	}
+	return born, died
+}
+
+func TestComputeNextGenerationMatchesReference(t *testing.T) {
+	// The soups straddle chunk edges at 0 and 64, on both sides of the
+	// origin.
+	soups := []struct {
+		name    string
+		bounds  grid.Rectangle
+		density float64
+	}{
+		{"origin", *grid.NewRectangle(-70, -70, 70, 70), 0.35},
+		{"chunk edge", *grid.NewRectangle(60, -4, 68, 130), 0.5},
+		{"sparse", *grid.NewRectangle(-300, -200, 250, 180), 0.02},
+		{"dense", *grid.NewRectangle(-10, -10, 140, 40), 0.8},
+	}
+
+	for _, s := range soups {
+		for seed := int64(1); seed <= 3; seed++ {
+			cells := soup(s.bounds, s.density, seed)
+			t.Run(fmt.Sprintf("%s/seed=%d", s.name, seed), func(t *testing.T) {
+				wantBorn, wantDied := referenceNext(Topology{Kind: TopologyInfinite}, cells)
+				wantBorn, wantDied = sorted(wantBorn), sorted(wantDied)
+
+				serialBorn, serialDied := ComputeNextGeneration(cells, 1)
+				if !reflect.DeepEqual(sorted(serialBorn), wantBorn) || !reflect.DeepEqual(sorted(serialDied), wantDied) {
+					t.Fatalf("serial result differs from grid.ComputeNextGrid: %d born, %d died, want %d born, %d died",
+						len(serialBorn), len(serialDied), len(wantBorn), len(wantDied))
+				}
+
+				for _, workers := range testWorkerCounts[1:] {
+					born, died := ComputeNextGeneration(cells, workers)
+					if !reflect.DeepEqual(born, serialBorn) || !reflect.DeepEqual(died, serialDied) {
+						t.Errorf("%d workers: result differs from the serial run", workers)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestTopologyComputeNextGenerationMatchesReference(t *testing.T) {
+	// The world spans several chunks and its edges are not chunk aligned.
+	bounds := *grid.NewRectangle(-40, -30, 89, 70)
+	kinds := []TopologyKind{TopologyInfinite, TopologyBounded, TopologyTorus, TopologyKlein}
+
+	for _, kind := range kinds {
+		topology, err := NewTopology(kind, bounds)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for seed := int64(1); seed <= 3; seed++ {
+			t.Run(fmt.Sprintf("%s/seed=%d", kind, seed), func(t *testing.T) {
+				cells := topology.clip(soup(bounds, 0.4, seed))
+
+				// Run a few generations so edge effects build up.
+				for gen := 0; gen < 4; gen++ {
+					wantBorn, wantDied := referenceNext(topology, cells)
+					wantBorn, wantDied = sorted(wantBorn), sorted(wantDied)
+
+					var serialBorn, serialDied []grid.Cell
+					for _, workers := range testWorkerCounts {
+						born, died := topology.ComputeNextGeneration(cells, workers)
+						if !reflect.DeepEqual(sorted(born), wantBorn) || !reflect.DeepEqual(sorted(died), wantDied) {
+							t.Fatalf("generation %d, %d workers: %d born, %d died, want %d born, %d died",
+								gen, workers, len(born), len(died), len(wantBorn), len(wantDied))
+						}
+						if workers == 1 {
+							serialBorn, serialDied = born, died
+						} else if !reflect.DeepEqual(born, serialBorn) || !reflect.DeepEqual(died, serialDied) {
+							t.Fatalf("generation %d, %d workers: result differs from the serial run", gen, workers)
+						}
+					}
+
+					cells = advance(cells, wantBorn, wantDied)
+				}
+			})
+		}
+	}
+}
+
+func advance(cells, born, died []grid.Cell) []grid.Cell {
+	dead := make(map[grid.Cell]bool, len(died))
+	for _, c := range died {
+		dead[c] = true
+	}
+
+	next := make([]grid.Cell, 0, len(cells)+len(born))
+	for _, c := range cells {
+		if !dead[c] {
+			next = append(next, c)
+		}
+	}
+	return append(next, born...)
+}
+
+func BenchmarkComputeNextGeneration(b *testing.B) {
+	cells := soup(*grid.NewRectangle(0, 0, 511, 511), 0.3, 1)
+
+	b.Run("reference", func(b *testing.B) {
+		g := grid.NewGrid()
+		for _, c := range cells {
+			g.SetCell(c.X, c.Y)
+		}
+		b.ResetTimer()
+		for range b.N {
+			g.ComputeNextGrid()
+		}
+	})
+
+	for _, workers := range testWorkerCounts {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for range b.N {
+				ComputeNextGeneration(cells, workers)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/henilmalaviya/gol/grid"
+)
+
+type TopologyKind string
+
+const (
+	// TopologyInfinite is the unbounded plane.
+	TopologyInfinite TopologyKind = "infinite"
+	// TopologyBounded is a rectangle whose surroundings are always dead.
+	TopologyBounded TopologyKind = "bounded"
+	// TopologyTorus wraps both axes.
+	TopologyTorus TopologyKind = "torus"
+	// TopologyKlein wraps the x axis and wraps the y axis with a mirror
+	// flip, gluing the rectangle into a Klein bottle.
+	TopologyKlein TopologyKind = "klein"
+)
+
+var ErrOutOfBounds = errors.New("cell is outside the world bounds")
+
+func ParseTopologyKind(kind string) (TopologyKind, error) {
+	switch TopologyKind(kind) {
+	case TopologyInfinite, TopologyBounded, TopologyTorus, TopologyKlein:
+		return TopologyKind(kind), nil
+	default:
+		return "", fmt.Errorf("unknown topology %q", kind)
+	}
+}
+
+// Topology describes the shape of the world. Bounds is ignored for the
+// infinite topology.
+type Topology struct {
+	Kind   TopologyKind
+	Bounds grid.Rectangle
+}
+
+func NewTopology(kind TopologyKind, bounds grid.Rectangle) (Topology, error) {
+	if kind == TopologyInfinite {
+		return Topology{Kind: kind}, nil
+	}
+
+	bounds = *bounds.Normalized()
+	if kind == TopologyTorus || kind == TopologyKlein {
+		// Narrower worlds would make a cell its own neighbour.
+		if bounds.Width() < 3 || bounds.Height() < 3 {
+			return Topology{}, fmt.Errorf("%s topology needs bounds of at least 3x3", kind)
+		}
+	}
+
+	return Topology{Kind: kind, Bounds: bounds}, nil
+}
+
+func (t Topology) IsBounded() bool {
+	return t.Kind != "" && t.Kind != TopologyInfinite
+}
+
+func (t Topology) Contains(x, y int) bool {
+	if !t.IsBounded() {
+		return true
+	}
+	return t.Bounds.PointInside(x, y)
+}
+
+// Check returns an error wrapping ErrOutOfBounds if the cell at (x, y) is
+// outside the world.
+func (t Topology) Check(x, y int) error {
+	if t.Contains(x, y) {
+		return nil
+	}
+	return fmt.Errorf("%w: (%d, %d) not in %v", ErrOutOfBounds, x, y, t.Bounds.ToNestedArray())
+}
+
+// clip returns the cells that lie inside the world.
+func (t Topology) clip(cells []grid.Cell) []grid.Cell {
+	if !t.IsBounded() {
+		return cells
+	}
+
+	clipped := cells[:0:0]
+	for _, c := range cells {
+		if t.Bounds.PointInside(c.X, c.Y) {
+			clipped = append(clipped, c)
+		}
+	}
+	return clipped
+}
+
+// ghostCells returns copies of the live cells on the edges of a wrapping
+// world, placed in the one cell wide ring around the bounds where the
+// opposite edge would be. Evolving the live cells together with their ghosts
+// on the plane gives the wrapped result inside the bounds.
+func (t Topology) ghostCells(cells []grid.Cell) []grid.Cell {
+	if t.Kind != TopologyTorus && t.Kind != TopologyKlein {
+		return nil
+	}
+
+	b := t.Bounds
+	width, height := b.Width(), b.Height()
+	halo := grid.Rectangle{X1: b.X1 - 1, Y1: b.Y1 - 1, X2: b.X2 + 1, Y2: b.Y2 + 1}
+
+	var ghosts []grid.Cell
+	for _, c := range cells {
+		if c.X != b.X1 && c.X != b.X2 && c.Y != b.Y1 && c.Y != b.Y2 {
+			continue
+		}
+
+		for dy := -1; dy <= 1; dy++ {
+			x := c.X
+			if dy != 0 && t.Kind == TopologyKlein {
+				x = b.X1 + b.X2 - c.X
+			}
+			for dx := -1; dx <= 1; dx++ {
+				if dx == 0 && dy == 0 {
+					continue
+				}
+				ghost := grid.Cell{X: x + dx*width, Y: c.Y + dy*height}
+				if ghost.Inside(&halo) {
+					ghosts = append(ghosts, ghost)
+				}
+			}
+		}
+	}
+	return ghosts
+}
+
+// ComputeNextGeneration evolves the live cells by one generation within the
+// topology. See the package level ComputeNextGeneration.
+func (t Topology) ComputeNextGeneration(cells []grid.Cell, workers int) ([]grid.Cell, []grid.Cell) {
+	if !t.IsBounded() {
+		return ComputeNextGeneration(cells, workers)
+	}
+
+	if ghosts := t.ghostCells(cells); len(ghosts) > 0 {
+		cells = append(cells[:len(cells):len(cells)], ghosts...)
+	}
+
+	bornCells, diedCells := ComputeNextGeneration(cells, workers)
+	return t.clip(bornCells), t.clip(diedCells)
+}
+
+func (t Topology) MarshalJSON() ([]byte, error) {
+	data := map[string]interface{}{
+		"kind": t.Kind,
+	}
+	if t.IsBounded() {
+		data["bounds"] = t.Bounds.ToNestedArray()
+	}
+	return json.Marshal(data)
+}
@@ -0,0 +1,197 @@
+package game
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/henilmalaviya/gol/grid"
+)
+
+var (
+	ErrZoneProtected = errors.New("cell is in a protected zone")
+	ErrZoneNotFound  = errors.New("zone not found")
+)
+
+// Zone is a protected rectangle that only its owner and allowed users may
+// edit. They are named by registered user, see Author.User. A nil ExpiresAt
+// never expires.
+type Zone struct {
+	ID           string
+	Bounds       grid.Rectangle
+	Owner        string
+	AllowedUsers []string
+	CreatedAt    time.Time
+	ExpiresAt    *time.Time
+}
+
+type zoneJSON struct {
+	ID           string     `json:"id"`
+	Bounds       [2][2]int  `json:"bounds"`
+	Owner        string     `json:"owner"`
+	AllowedUsers []string   `json:"allowed_users,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+}
+
+func (z Zone) MarshalJSON() ([]byte, error) {
+	return json.Marshal(zoneJSON{
+		ID:           z.ID,
+		Bounds:       z.Bounds.ToNestedArray(),
+		Owner:        z.Owner,
+		AllowedUsers: z.AllowedUsers,
+		CreatedAt:    z.CreatedAt,
+		ExpiresAt:    z.ExpiresAt,
+	})
+}
+
+func (z *Zone) UnmarshalJSON(data []byte) error {
+	var raw zoneJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*z = Zone{
+		ID:           raw.ID,
+		Bounds:       *grid.NewRectangle(raw.Bounds[0][0], raw.Bounds[0][1], raw.Bounds[1][0], raw.Bounds[1][1]),
+		Owner:        raw.Owner,
+		AllowedUsers: raw.AllowedUsers,
+		CreatedAt:    raw.CreatedAt,
+		ExpiresAt:    raw.ExpiresAt,
+	}
+	return nil
+}
+
+func (z Zone) Expired(now time.Time) bool {
+	return z.ExpiresAt != nil && !now.Before(*z.ExpiresAt)
+}
+
+// Allows reports whether the verified user may edit inside the zone.
+func (z Zone) Allows(user string) bool {
+	if user == "" {
+		return false
+	}
+	return user == z.Owner || slices.Contains(z.AllowedUsers, user)
+}
+
+// newID returns a short random identifier starting with prefix.
+func newID(prefix string) string {
+	var b [4]byte
+	rand.Read(b[:])
+	return prefix + hex.EncodeToString(b[:])
+}
+
+// AddZone stores a new zone and returns it with its id and creation time set.
+func (m *Manager) AddZone(zone Zone) Zone {
+	zone.ID = newID("z")
+	zone.Bounds = *zone.Bounds.Normalized()
+	zone.CreatedAt = time.Now()
+
+	m.zonesMutex.Lock()
+	m.zones[zone.ID] = zone
+	m.zonesMutex.Unlock()
+
+	return zone
+}
+
+func (m *Manager) RemoveZone(id string) error {
+	m.zonesMutex.Lock()
+	defer m.zonesMutex.Unlock()
+
+	if _, ok := m.zones[id]; !ok {
+		return fmt.Errorf("%w: %s", ErrZoneNotFound, id)
+	}
+	delete(m.zones, id)
+	return nil
+}
+
+// Zones returns the zones that have not expired, oldest first. Expired
+// zones are dropped.
+func (m *Manager) Zones() []Zone {
+	m.zonesMutex.Lock()
+	defer m.zonesMutex.Unlock()
+
+	now := time.Now()
+	zones := make([]Zone, 0, len(m.zones))
+	for id, z := range m.zones {
+		if z.Expired(now) {
+			delete(m.zones, id)
+			continue
+		}
+		zones = append(zones, z)
+	}
+
+	sort.Slice(zones, func(i, j int) bool {
+		if !zones[i].CreatedAt.Equal(zones[j].CreatedAt) {
+			return zones[i].CreatedAt.Before(zones[j].CreatedAt)
+		}
+		return zones[i].ID < zones[j].ID
+	})
+	return zones
+}
+
+// CheckZones returns an error wrapping ErrZoneProtected if any of the cells
+// lies in a zone the author may not edit. Admins may edit anywhere.
+func (m *Manager) CheckZones(cells []grid.Cell, author Author) error {
+	if author.Admin {
+		return nil
+	}
+
+	zones := m.Zones()
+	for _, z := range zones {
+		if z.Allows(author.User) {
+			continue
+		}
+		for _, c := range cells {
+			if c.Inside(&z.Bounds) {
+				return fmt.Errorf("%w: (%d, %d) is in zone %s owned by %s", ErrZoneProtected, c.X, c.Y, z.ID, z.Owner)
+			}
+		}
+	}
+	return nil
+}
+
+// loadZones replaces the zones with the ones from a snapshot.
+func (m *Manager) loadZones(zones []Zone) {
+	m.zonesMutex.Lock()
+	defer m.zonesMutex.Unlock()
+
+	clear(m.zones)
+	for _, z := range zones {
+		m.zones[z.ID] = z
+	}
+}
+
+// CheckZonesRect returns an error wrapping ErrZoneProtected if rect overlaps
+// a zone the author may not edit. Admins may edit anywhere.
+func (m *Manager) CheckZonesRect(rect grid.Rectangle, author Author) error {
+	if author.Admin {
+		return nil
+	}
+
+	r := rect.Normalized()
+	for _, z := range m.Zones() {
+		if z.Allows(author.User) {
+			continue
+		}
+		if r.X1 <= z.Bounds.X2 && z.Bounds.X1 <= r.X2 && r.Y1 <= z.Bounds.Y2 && z.Bounds.Y1 <= r.Y2 {
+			return fmt.Errorf("%w: region overlaps zone %s owned by %s", ErrZoneProtected, z.ID, z.Owner)
+		}
+	}
+	return nil
+}
+
+// checkEditZonesLocked is CheckZones for the cells of edits. The caller must
+// hold m.mutex, so the zones are checked as the edit is applied.
+func (m *Manager) checkEditZonesLocked(edits []CellEdit, author Author) error {
+	cells := make([]grid.Cell, len(edits))
+	for i, e := range edits {
+		cells[i] = e.Cell
+	}
+	return m.CheckZones(cells, author)
+}
@@ -0,0 +1,75 @@
+package game
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/henilmalaviya/gol/grid"
+)
+
+func TestEditInZone(t *testing.T) {
+	m := NewManager()
+	m.AddZone(Zone{Bounds: *grid.NewRectangle(0, 0, 9, 9), Owner: "carol"})
+	cell := []grid.Cell{{X: 5, Y: 5}}
+
+	if _, err := m.EditCells(cell, true, alice); !errors.Is(err, ErrZoneProtected) {
+		t.Errorf("edit by a stranger: got %v, want %v", err, ErrZoneProtected)
+	}
+	if _, err := m.Stamp(nil, *grid.NewRectangle(8, 8, 12, 12), StampModeReplace, alice); !errors.Is(err, ErrZoneProtected) {
+		t.Errorf("stamp overlapping the zone: got %v, want %v", err, ErrZoneProtected)
+	}
+
+	for _, author := range []Author{
+		{ID: "c3", Name: "carol", User: "carol"},
+		{ID: "c4", Admin: true},
+	} {
+		if _, err := m.EditCells(cell, true, author); err != nil {
+			t.Errorf("edit by %+v: %v", author, err)
+		}
+	}
+}
+
+func TestZoneCheckedWhenEditApplies(t *testing.T) {
+	m := NewManager()
+	m.openEditQueue()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := m.EditCells([]grid.Cell{{X: 5, Y: 5}}, true, alice)
+		done <- err
+	}()
+
+	// The zone appears while the edit waits for the tick boundary.
+	for deadline := time.Now().Add(2 * time.Second); ; {
+		m.edits.mutex.Lock()
+		queued := len(m.edits.pending)
+		m.edits.mutex.Unlock()
+		if queued > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the edit was not queued")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	m.AddZone(Zone{Bounds: *grid.NewRectangle(0, 0, 9, 9), Owner: "carol"})
+	m.applyPendingEdits()
+
+	if err := <-done; !errors.Is(err, ErrZoneProtected) {
+		t.Fatalf("got %v, want %v", err, ErrZoneProtected)
+	}
+	if alive(m, grid.Cell{X: 5, Y: 5})[0] {
+		t.Error("the cell in the zone was set")
+	}
+}
+
+func TestUndoIntoZone(t *testing.T) {
+	m := NewManager()
+	m.EditCells([]grid.Cell{{X: 5, Y: 5}}, true, alice)
+	m.AddZone(Zone{Bounds: *grid.NewRectangle(0, 0, 9, 9), Owner: "carol"})
+
+	if _, _, err := m.Undo(alice, nil); !errors.Is(err, ErrZoneProtected) {
+		t.Errorf("got %v, want %v", err, ErrZoneProtected)
+	}
+}
@@ -1,52 +1,104 @@
 package main
 
 import (
-	"net/http"
-	"time"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
 
 	"github.com/henilmalaviya/golw/env"
-	"github.com/henilmalaviya/golw/game"
-	"github.com/henilmalaviya/golw/server"
 	"github.com/henilmalaviya/golw/util"
 )
 
-func main() {
-	logger := util.GetLogger()
-	logger.Info("Starting Game of Life WebSocket server", "log_level", env.Get().LogLevel)
+// command is a subcommand of the golw binary.
+type command struct {
+	summary string
+	run     func(args []string) error
+}
+
+var commands = map[string]command{
+	"serve":    {summary: "run the game server (the default)", run: runServe},
+	"snapshot": {summary: "list, inspect, convert and prune saved snapshots", run: runSnapshot},
+	"export":   {summary: "export the cells of a snapshot as a pattern", run: runExport},
+	"import":   {summary: "add a pattern to the world as a new snapshot", run: runImport},
+	"bench":    {summary: "measure tick performance on a random world", run: runBench},
+	"view":     {summary: "watch and edit a running server in the terminal", run: runView},
+}
 
-	gm := game.NewManager()
-	logger.Info("Game manager initialized")
+// commandOrder is the order commands are listed in the usage.
+var commandOrder = []string{"serve", "snapshot", "export", "import", "bench", "view"}
 
-	saveManager := game.NewSaveManager(gm)
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: golw [command] [flags]\n\nCommands:\n")
+	for _, name := range commandOrder {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun golw <command> -h for the flags of a command.\n")
+}
 
-	if err := saveManager.LoadLatest(); err != nil {
-		logger.Error("Failed to load latest snapshot", "error", err)
-		// load a blinker pattern to start with
-		gm.GetGame().GetGrid().SetCell(0, 1)
-		gm.GetGame().GetGrid().SetCell(0, 0)
-		gm.GetGame().GetGrid().SetCell(0, -1)
+// usageFor returns a usage function for a command's flag set.
+func usageFor(flags *flag.FlagSet, synopsis, description string) func() {
+	return func() {
+		fmt.Fprintf(flags.Output(), "Usage: golw %s\n\n%s\n\nFlags:\n", synopsis, description)
+		flags.PrintDefaults()
+	}
+}
 
-		logger.Info("Initialized game with default blinker pattern")
+// settingFlags adds a flag for each of the named settings, as well as
+// -config for the config file. Flags take precedence over the environment
+// and the config file, so the returned function applies them by setting the
+// environment and loading the settings again. It returns what is wrong with
+// the settings, if anything.
+func settingFlags(flags *flag.FlagSet, keys ...string) func() error {
+	config := flags.String("config", "", "config file, overrides CONFIG_FILE")
 
-	} else {
-		logger.Info("Loaded latest snapshot successfully")
-		logger.Info("Current game stats", "stats", gm.GetStats())
+	values := make(map[string]*string, len(keys))
+	for _, key := range keys {
+		name := strings.ToLower(strings.ReplaceAll(key, "_", "-"))
+		values[name] = flags.String(name, "", "overrides "+key)
 	}
 
-	saveManager.StartSaving()
+	return func() error {
+		if *config != "" {
+			os.Setenv("CONFIG_FILE", *config)
+		}
+		flags.Visit(func(f *flag.Flag) {
+			if value, ok := values[f.Name]; ok {
+				os.Setenv(strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_")), *value)
+			}
+		})
 
-	gm.Start(time.Millisecond * time.Duration(env.Get().TickSpeed))
-	logger.Info("Game tick started", "interval", env.Get().TickSpeed)
+		err := env.Load()
+		util.SetLogLevel(env.Get().LogLevel)
+		return err
+	}
+}
+
+func main() {
+	name, args := "serve", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
 
-	http.HandleFunc(env.Get().WSEndpoint, server.WebsocketHandler(gm))
-	logger.Info("WebSocket endpoint registered", "endpoint", env.Get().WSEndpoint)
+	if name == "help" {
+		usage()
+		return
+	}
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "golw: unknown command %q\n\n", name)
+		usage()
+		os.Exit(2)
+	}
 
-	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte("OK"))
-	})
+	// Only the server logs to stdout; the other commands print their
+	// results there.
+	if name != "serve" {
+		util.GetLogger().SetOutput(os.Stderr)
+	}
 
-	logger.Info("Starting HTTP server", "port", env.Get().Port)
-	if err := http.ListenAndServe(":"+env.Get().Port, nil); err != nil {
-		logger.Fatal("Failed to start server", "error", err)
+	if err := cmd.run(args); err != nil {
+		fmt.Fprintf(os.Stderr, "golw %s: %v\n", name, err)
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,5 @@
+// Package rpc holds the gRPC API of the server, generated from golw.proto.
+// The service is implemented by the server package.
+package rpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative golw.proto
@@ -0,0 +1,1145 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        v5.29.3
+// source: golw.proto
+
+package rpc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Cell struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	X             int64                  `protobuf:"zigzag64,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y             int64                  `protobuf:"zigzag64,2,opt,name=y,proto3" json:"y,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Cell) Reset() {
+	*x = Cell{}
+	mi := &file_golw_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Cell) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Cell) ProtoMessage() {}
+
+func (x *Cell) ProtoReflect() protoreflect.Message {
+	mi := &file_golw_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Cell.ProtoReflect.Descriptor instead.
+func (*Cell) Descriptor() ([]byte, []int) {
+	return file_golw_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Cell) GetX() int64 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *Cell) GetY() int64 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+// Rectangle is the cells from min to max, both included.
+type Rectangle struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Min           *Cell                  `protobuf:"bytes,1,opt,name=min,proto3" json:"min,omitempty"`
+	Max           *Cell                  `protobuf:"bytes,2,opt,name=max,proto3" json:"max,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Rectangle) Reset() {
+	*x = Rectangle{}
+	mi := &file_golw_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Rectangle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rectangle) ProtoMessage() {}
+
+func (x *Rectangle) ProtoReflect() protoreflect.Message {
+	mi := &file_golw_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rectangle.ProtoReflect.Descriptor instead.
+func (*Rectangle) Descriptor() ([]byte, []int) {
+	return file_golw_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Rectangle) GetMin() *Cell {
+	if x != nil {
+		return x.Min
+	}
+	return nil
+}
+
+func (x *Rectangle) GetMax() *Cell {
+	if x != nil {
+		return x.Max
+	}
+	return nil
+}
+
+// Author is who an edit is attributed to. The id is that of the WebSocket
+// or HTTP connection the edit came through, and empty for edits made over
+// gRPC.
+type Author struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Color         string                 `protobuf:"bytes,3,opt,name=color,proto3" json:"color,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Author) Reset() {
+	*x = Author{}
+	mi := &file_golw_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Author) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Author) ProtoMessage() {}
+
+func (x *Author) ProtoReflect() protoreflect.Message {
+	mi := &file_golw_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Author.ProtoReflect.Descriptor instead.
+func (*Author) Descriptor() ([]byte, []int) {
+	return file_golw_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Author) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Author) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Author) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+type EditRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Cells []*Cell                `protobuf:"bytes,1,rep,name=cells,proto3" json:"cells,omitempty"`
+	// Name and color attribute the edit, as with the auth command. Both are
+	// optional. A registered user's name needs its token as a bearer token in
+	// the authorization metadata.
+	Name          string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Color         string `protobuf:"bytes,3,opt,name=color,proto3" json:"color,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EditRequest) Reset() {
+	*x = EditRequest{}
+	mi := &file_golw_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EditRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EditRequest) ProtoMessage() {}
+
+func (x *EditRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_golw_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EditRequest.ProtoReflect.Descriptor instead.
+func (*EditRequest) Descriptor() ([]byte, []int) {
+	return file_golw_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *EditRequest) GetCells() []*Cell {
+	if x != nil {
+		return x.Cells
+	}
+	return nil
+}
+
+func (x *EditRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *EditRequest) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+type EditReply struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Generation is the generation the edit was applied at.
+	Generation int64 `protobuf:"varint,1,opt,name=generation,proto3" json:"generation,omitempty"`
+	// Changed is the number of cells whose state changed.
+	Changed       int64 `protobuf:"varint,2,opt,name=changed,proto3" json:"changed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EditReply) Reset() {
+	*x = EditReply{}
+	mi := &file_golw_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EditReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EditReply) ProtoMessage() {}
+
+func (x *EditReply) ProtoReflect() protoreflect.Message {
+	mi := &file_golw_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EditReply.ProtoReflect.Descriptor instead.
+func (*EditReply) Descriptor() ([]byte, []int) {
+	return file_golw_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *EditReply) GetGeneration() int64 {
+	if x != nil {
+		return x.Generation
+	}
+	return 0
+}
+
+func (x *EditReply) GetChanged() int64 {
+	if x != nil {
+		return x.Changed
+	}
+	return 0
+}
+
+type SyncRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Bounds        *Rectangle             `protobuf:"bytes,1,opt,name=bounds,proto3" json:"bounds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncRequest) Reset() {
+	*x = SyncRequest{}
+	mi := &file_golw_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncRequest) ProtoMessage() {}
+
+func (x *SyncRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_golw_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncRequest.ProtoReflect.Descriptor instead.
+func (*SyncRequest) Descriptor() ([]byte, []int) {
+	return file_golw_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SyncRequest) GetBounds() *Rectangle {
+	if x != nil {
+		return x.Bounds
+	}
+	return nil
+}
+
+type SyncReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cells         []*Cell                `protobuf:"bytes,1,rep,name=cells,proto3" json:"cells,omitempty"`
+	Stats         *Stats                 `protobuf:"bytes,2,opt,name=stats,proto3" json:"stats,omitempty"`
+	Topology      *Topology              `protobuf:"bytes,3,opt,name=topology,proto3" json:"topology,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncReply) Reset() {
+	*x = SyncReply{}
+	mi := &file_golw_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncReply) ProtoMessage() {}
+
+func (x *SyncReply) ProtoReflect() protoreflect.Message {
+	mi := &file_golw_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncReply.ProtoReflect.Descriptor instead.
+func (*SyncReply) Descriptor() ([]byte, []int) {
+	return file_golw_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SyncReply) GetCells() []*Cell {
+	if x != nil {
+		return x.Cells
+	}
+	return nil
+}
+
+func (x *SyncReply) GetStats() *Stats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+func (x *SyncReply) GetTopology() *Topology {
+	if x != nil {
+		return x.Topology
+	}
+	return nil
+}
+
+type Stats struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Generation  int64                  `protobuf:"varint,1,opt,name=generation,proto3" json:"generation,omitempty"`
+	BirthCount  int64                  `protobuf:"varint,2,opt,name=birth_count,json=birthCount,proto3" json:"birth_count,omitempty"`
+	DeathCount  int64                  `protobuf:"varint,3,opt,name=death_count,json=deathCount,proto3" json:"death_count,omitempty"`
+	CulledCount int64                  `protobuf:"varint,4,opt,name=culled_count,json=culledCount,proto3" json:"culled_count,omitempty"`
+	Population  int64                  `protobuf:"varint,5,opt,name=population,proto3" json:"population,omitempty"`
+	// Bounds encloses the live cells. It is unset while no cell is alive.
+	Bounds *Rectangle `protobuf:"bytes,6,opt,name=bounds,proto3" json:"bounds,omitempty"`
+	// Period is the world's period once a state repeats, 1 for a still life
+	// and 0 while it is not periodic. StabilizedAt is the first generation of
+	// the repeating cycle.
+	Period         int64   `protobuf:"varint,7,opt,name=period,proto3" json:"period,omitempty"`
+	StabilizedAt   int64   `protobuf:"varint,8,opt,name=stabilized_at,json=stabilizedAt,proto3" json:"stabilized_at,omitempty"`
+	TickRate       float64 `protobuf:"fixed64,9,opt,name=tick_rate,json=tickRate,proto3" json:"tick_rate,omitempty"`
+	TickIntervalMs float64 `protobuf:"fixed64,10,opt,name=tick_interval_ms,json=tickIntervalMs,proto3" json:"tick_interval_ms,omitempty"`
+	LastTickMs     float64 `protobuf:"fixed64,11,opt,name=last_tick_ms,json=lastTickMs,proto3" json:"last_tick_ms,omitempty"`
+	TickOverruns   int64   `protobuf:"varint,12,opt,name=tick_overruns,json=tickOverruns,proto3" json:"tick_overruns,omitempty"`
+	SkippedTicks   int64   `protobuf:"varint,13,opt,name=skipped_ticks,json=skippedTicks,proto3" json:"skipped_ticks,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Stats) Reset() {
+	*x = Stats{}
+	mi := &file_golw_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Stats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Stats) ProtoMessage() {}
+
+func (x *Stats) ProtoReflect() protoreflect.Message {
+	mi := &file_golw_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Stats.ProtoReflect.Descriptor instead.
+func (*Stats) Descriptor() ([]byte, []int) {
+	return file_golw_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Stats) GetGeneration() int64 {
+	if x != nil {
+		return x.Generation
+	}
+	return 0
+}
+
+func (x *Stats) GetBirthCount() int64 {
+	if x != nil {
+		return x.BirthCount
+	}
+	return 0
+}
+
+func (x *Stats) GetDeathCount() int64 {
+	if x != nil {
+		return x.DeathCount
+	}
+	return 0
+}
+
+func (x *Stats) GetCulledCount() int64 {
+	if x != nil {
+		return x.CulledCount
+	}
+	return 0
+}
+
+func (x *Stats) GetPopulation() int64 {
+	if x != nil {
+		return x.Population
+	}
+	return 0
+}
+
+func (x *Stats) GetBounds() *Rectangle {
+	if x != nil {
+		return x.Bounds
+	}
+	return nil
+}
+
+func (x *Stats) GetPeriod() int64 {
+	if x != nil {
+		return x.Period
+	}
+	return 0
+}
+
+func (x *Stats) GetStabilizedAt() int64 {
+	if x != nil {
+		return x.StabilizedAt
+	}
+	return 0
+}
+
+func (x *Stats) GetTickRate() float64 {
+	if x != nil {
+		return x.TickRate
+	}
+	return 0
+}
+
+func (x *Stats) GetTickIntervalMs() float64 {
+	if x != nil {
+		return x.TickIntervalMs
+	}
+	return 0
+}
+
+func (x *Stats) GetLastTickMs() float64 {
+	if x != nil {
+		return x.LastTickMs
+	}
+	return 0
+}
+
+func (x *Stats) GetTickOverruns() int64 {
+	if x != nil {
+		return x.TickOverruns
+	}
+	return 0
+}
+
+func (x *Stats) GetSkippedTicks() int64 {
+	if x != nil {
+		return x.SkippedTicks
+	}
+	return 0
+}
+
+type Topology struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Kind is infinite, bounded, torus or klein.
+	Kind string `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	// Bounds is the world's extent, unset for an infinite world.
+	Bounds        *Rectangle `protobuf:"bytes,2,opt,name=bounds,proto3" json:"bounds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Topology) Reset() {
+	*x = Topology{}
+	mi := &file_golw_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Topology) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Topology) ProtoMessage() {}
+
+func (x *Topology) ProtoReflect() protoreflect.Message {
+	mi := &file_golw_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Topology.ProtoReflect.Descriptor instead.
+func (*Topology) Descriptor() ([]byte, []int) {
+	return file_golw_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Topology) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Topology) GetBounds() *Rectangle {
+	if x != nil {
+		return x.Bounds
+	}
+	return nil
+}
+
+type ObserveRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Bounds *Rectangle             `protobuf:"bytes,1,opt,name=bounds,proto3" json:"bounds,omitempty"`
+	// DetectPeriod reports when the observed region becomes periodic.
+	DetectPeriod  bool `protobuf:"varint,2,opt,name=detect_period,json=detectPeriod,proto3" json:"detect_period,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ObserveRequest) Reset() {
+	*x = ObserveRequest{}
+	mi := &file_golw_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ObserveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ObserveRequest) ProtoMessage() {}
+
+func (x *ObserveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_golw_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ObserveRequest.ProtoReflect.Descriptor instead.
+func (*ObserveRequest) Descriptor() ([]byte, []int) {
+	return file_golw_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ObserveRequest) GetBounds() *Rectangle {
+	if x != nil {
+		return x.Bounds
+	}
+	return nil
+}
+
+func (x *ObserveRequest) GetDetectPeriod() bool {
+	if x != nil {
+		return x.DetectPeriod
+	}
+	return false
+}
+
+type ObserveEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*ObserveEvent_Tick
+	//	*ObserveEvent_SetCell
+	//	*ObserveEvent_ClearCell
+	//	*ObserveEvent_Periodic
+	Event         isObserveEvent_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ObserveEvent) Reset() {
+	*x = ObserveEvent{}
+	mi := &file_golw_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ObserveEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ObserveEvent) ProtoMessage() {}
+
+func (x *ObserveEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_golw_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ObserveEvent.ProtoReflect.Descriptor instead.
+func (*ObserveEvent) Descriptor() ([]byte, []int) {
+	return file_golw_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ObserveEvent) GetEvent() isObserveEvent_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *ObserveEvent) GetTick() *Tick {
+	if x != nil {
+		if x, ok := x.Event.(*ObserveEvent_Tick); ok {
+			return x.Tick
+		}
+	}
+	return nil
+}
+
+func (x *ObserveEvent) GetSetCell() *CellEdit {
+	if x != nil {
+		if x, ok := x.Event.(*ObserveEvent_SetCell); ok {
+			return x.SetCell
+		}
+	}
+	return nil
+}
+
+func (x *ObserveEvent) GetClearCell() *CellEdit {
+	if x != nil {
+		if x, ok := x.Event.(*ObserveEvent_ClearCell); ok {
+			return x.ClearCell
+		}
+	}
+	return nil
+}
+
+func (x *ObserveEvent) GetPeriodic() *Periodic {
+	if x != nil {
+		if x, ok := x.Event.(*ObserveEvent_Periodic); ok {
+			return x.Periodic
+		}
+	}
+	return nil
+}
+
+type isObserveEvent_Event interface {
+	isObserveEvent_Event()
+}
+
+type ObserveEvent_Tick struct {
+	Tick *Tick `protobuf:"bytes,1,opt,name=tick,proto3,oneof"`
+}
+
+type ObserveEvent_SetCell struct {
+	SetCell *CellEdit `protobuf:"bytes,2,opt,name=set_cell,json=setCell,proto3,oneof"`
+}
+
+type ObserveEvent_ClearCell struct {
+	ClearCell *CellEdit `protobuf:"bytes,3,opt,name=clear_cell,json=clearCell,proto3,oneof"`
+}
+
+type ObserveEvent_Periodic struct {
+	Periodic *Periodic `protobuf:"bytes,4,opt,name=periodic,proto3,oneof"`
+}
+
+func (*ObserveEvent_Tick) isObserveEvent_Event() {}
+
+func (*ObserveEvent_SetCell) isObserveEvent_Event() {}
+
+func (*ObserveEvent_ClearCell) isObserveEvent_Event() {}
+
+func (*ObserveEvent_Periodic) isObserveEvent_Event() {}
+
+// Tick is what a generation changed inside the observed region. Generations
+// that change nothing there are not sent.
+type Tick struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Generation    int64                  `protobuf:"varint,1,opt,name=generation,proto3" json:"generation,omitempty"`
+	Born          []*Cell                `protobuf:"bytes,2,rep,name=born,proto3" json:"born,omitempty"`
+	Died          []*Cell                `protobuf:"bytes,3,rep,name=died,proto3" json:"died,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Tick) Reset() {
+	*x = Tick{}
+	mi := &file_golw_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Tick) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tick) ProtoMessage() {}
+
+func (x *Tick) ProtoReflect() protoreflect.Message {
+	mi := &file_golw_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tick.ProtoReflect.Descriptor instead.
+func (*Tick) Descriptor() ([]byte, []int) {
+	return file_golw_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Tick) GetGeneration() int64 {
+	if x != nil {
+		return x.Generation
+	}
+	return 0
+}
+
+func (x *Tick) GetBorn() []*Cell {
+	if x != nil {
+		return x.Born
+	}
+	return nil
+}
+
+func (x *Tick) GetDied() []*Cell {
+	if x != nil {
+		return x.Died
+	}
+	return nil
+}
+
+// CellEdit is a cell set or cleared by an edit.
+type CellEdit struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Cell  *Cell                  `protobuf:"bytes,1,opt,name=cell,proto3" json:"cell,omitempty"`
+	// Author is unset for edits that are not attributed.
+	Author        *Author `protobuf:"bytes,2,opt,name=author,proto3" json:"author,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CellEdit) Reset() {
+	*x = CellEdit{}
+	mi := &file_golw_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CellEdit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CellEdit) ProtoMessage() {}
+
+func (x *CellEdit) ProtoReflect() protoreflect.Message {
+	mi := &file_golw_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CellEdit.ProtoReflect.Descriptor instead.
+func (*CellEdit) Descriptor() ([]byte, []int) {
+	return file_golw_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CellEdit) GetCell() *Cell {
+	if x != nil {
+		return x.Cell
+	}
+	return nil
+}
+
+func (x *CellEdit) GetAuthor() *Author {
+	if x != nil {
+		return x.Author
+	}
+	return nil
+}
+
+// Periodic reports that the observed region repeats.
+type Periodic struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Generation   int64                  `protobuf:"varint,1,opt,name=generation,proto3" json:"generation,omitempty"`
+	Period       int64                  `protobuf:"varint,2,opt,name=period,proto3" json:"period,omitempty"`
+	StabilizedAt int64                  `protobuf:"varint,3,opt,name=stabilized_at,json=stabilizedAt,proto3" json:"stabilized_at,omitempty"`
+	// Region is the part of the world that repeats, when known.
+	Region        *Rectangle `protobuf:"bytes,4,opt,name=region,proto3" json:"region,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Periodic) Reset() {
+	*x = Periodic{}
+	mi := &file_golw_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Periodic) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Periodic) ProtoMessage() {}
+
+func (x *Periodic) ProtoReflect() protoreflect.Message {
+	mi := &file_golw_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Periodic.ProtoReflect.Descriptor instead.
+func (*Periodic) Descriptor() ([]byte, []int) {
+	return file_golw_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Periodic) GetGeneration() int64 {
+	if x != nil {
+		return x.Generation
+	}
+	return 0
+}
+
+func (x *Periodic) GetPeriod() int64 {
+	if x != nil {
+		return x.Period
+	}
+	return 0
+}
+
+func (x *Periodic) GetStabilizedAt() int64 {
+	if x != nil {
+		return x.StabilizedAt
+	}
+	return 0
+}
+
+func (x *Periodic) GetRegion() *Rectangle {
+	if x != nil {
+		return x.Region
+	}
+	return nil
+}
+
+var File_golw_proto protoreflect.FileDescriptor
+
+const file_golw_proto_rawDesc = "" +
+	"\n" +
+	"\n" +
+	"golw.proto\x12\agolw.v1\"\"\n" +
+	"\x04Cell\x12\f\n" +
+	"\x01x\x18\x01 \x01(\x12R\x01x\x12\f\n" +
+	"\x01y\x18\x02 \x01(\x12R\x01y\"M\n" +
+	"\tRectangle\x12\x1f\n" +
+	"\x03min\x18\x01 \x01(\v2\r.golw.v1.CellR\x03min\x12\x1f\n" +
+	"\x03max\x18\x02 \x01(\v2\r.golw.v1.CellR\x03max\"B\n" +
+	"\x06Author\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05color\x18\x03 \x01(\tR\x05color\"\\\n" +
+	"\vEditRequest\x12#\n" +
+	"\x05cells\x18\x01 \x03(\v2\r.golw.v1.CellR\x05cells\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05color\x18\x03 \x01(\tR\x05color\"E\n" +
+	"\tEditReply\x12\x1e\n" +
+	"\n" +
+	"generation\x18\x01 \x01(\x03R\n" +
+	"generation\x12\x18\n" +
+	"\achanged\x18\x02 \x01(\x03R\achanged\"9\n" +
+	"\vSyncRequest\x12*\n" +
+	"\x06bounds\x18\x01 \x01(\v2\x12.golw.v1.RectangleR\x06bounds\"\x85\x01\n" +
+	"\tSyncReply\x12#\n" +
+	"\x05cells\x18\x01 \x03(\v2\r.golw.v1.CellR\x05cells\x12$\n" +
+	"\x05stats\x18\x02 \x01(\v2\x0e.golw.v1.StatsR\x05stats\x12-\n" +
+	"\btopology\x18\x03 \x01(\v2\x11.golw.v1.TopologyR\btopology\"\xc8\x03\n" +
+	"\x05Stats\x12\x1e\n" +
+	"\n" +
+	"generation\x18\x01 \x01(\x03R\n" +
+	"generation\x12\x1f\n" +
+	"\vbirth_count\x18\x02 \x01(\x03R\n" +
+	"birthCount\x12\x1f\n" +
+	"\vdeath_count\x18\x03 \x01(\x03R\n" +
+	"deathCount\x12!\n" +
+	"\fculled_count\x18\x04 \x01(\x03R\vculledCount\x12\x1e\n" +
+	"\n" +
+	"population\x18\x05 \x01(\x03R\n" +
+	"population\x12*\n" +
+	"\x06bounds\x18\x06 \x01(\v2\x12.golw.v1.RectangleR\x06bounds\x12\x16\n" +
+	"\x06period\x18\a \x01(\x03R\x06period\x12#\n" +
+	"\rstabilized_at\x18\b \x01(\x03R\fstabilizedAt\x12\x1b\n" +
+	"\ttick_rate\x18\t \x01(\x01R\btickRate\x12(\n" +
+	"\x10tick_interval_ms\x18\n" +
+	" \x01(\x01R\x0etickIntervalMs\x12 \n" +
+	"\flast_tick_ms\x18\v \x01(\x01R\n" +
+	"lastTickMs\x12#\n" +
+	"\rtick_overruns\x18\f \x01(\x03R\ftickOverruns\x12#\n" +
+	"\rskipped_ticks\x18\r \x01(\x03R\fskippedTicks\"J\n" +
+	"\bTopology\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12*\n" +
+	"\x06bounds\x18\x02 \x01(\v2\x12.golw.v1.RectangleR\x06bounds\"a\n" +
+	"\x0eObserveRequest\x12*\n" +
+	"\x06bounds\x18\x01 \x01(\v2\x12.golw.v1.RectangleR\x06bounds\x12#\n" +
+	"\rdetect_period\x18\x02 \x01(\bR\fdetectPeriod\"\xd1\x01\n" +
+	"\fObserveEvent\x12#\n" +
+	"\x04tick\x18\x01 \x01(\v2\r.golw.v1.TickH\x00R\x04tick\x12.\n" +
+	"\bset_cell\x18\x02 \x01(\v2\x11.golw.v1.CellEditH\x00R\asetCell\x122\n" +
+	"\n" +
+	"clear_cell\x18\x03 \x01(\v2\x11.golw.v1.CellEditH\x00R\tclearCell\x12/\n" +
+	"\bperiodic\x18\x04 \x01(\v2\x11.golw.v1.PeriodicH\x00R\bperiodicB\a\n" +
+	"\x05event\"l\n" +
+	"\x04Tick\x12\x1e\n" +
+	"\n" +
+	"generation\x18\x01 \x01(\x03R\n" +
+	"generation\x12!\n" +
+	"\x04born\x18\x02 \x03(\v2\r.golw.v1.CellR\x04born\x12!\n" +
+	"\x04died\x18\x03 \x03(\v2\r.golw.v1.CellR\x04died\"V\n" +
+	"\bCellEdit\x12!\n" +
+	"\x04cell\x18\x01 \x01(\v2\r.golw.v1.CellR\x04cell\x12'\n" +
+	"\x06author\x18\x02 \x01(\v2\x0f.golw.v1.AuthorR\x06author\"\x93\x01\n" +
+	"\bPeriodic\x12\x1e\n" +
+	"\n" +
+	"generation\x18\x01 \x01(\x03R\n" +
+	"generation\x12\x16\n" +
+	"\x06period\x18\x02 \x01(\x03R\x06period\x12#\n" +
+	"\rstabilized_at\x18\x03 \x01(\x03R\fstabilizedAt\x12*\n" +
+	"\x06region\x18\x04 \x01(\v2\x12.golw.v1.RectangleR\x06region2\xe3\x01\n" +
+	"\x04Game\x124\n" +
+	"\bSetCells\x12\x14.golw.v1.EditRequest\x1a\x12.golw.v1.EditReply\x126\n" +
+	"\n" +
+	"ClearCells\x12\x14.golw.v1.EditRequest\x1a\x12.golw.v1.EditReply\x120\n" +
+	"\x04Sync\x12\x14.golw.v1.SyncRequest\x1a\x12.golw.v1.SyncReply\x12;\n" +
+	"\aObserve\x12\x17.golw.v1.ObserveRequest\x1a\x15.golw.v1.ObserveEvent0\x01B#Z!github.com/henilmalaviya/golw/rpcb\x06proto3"
+
+var (
+	file_golw_proto_rawDescOnce sync.Once
+	file_golw_proto_rawDescData []byte
+)
+
+func file_golw_proto_rawDescGZIP() []byte {
+	file_golw_proto_rawDescOnce.Do(func() {
+		file_golw_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_golw_proto_rawDesc), len(file_golw_proto_rawDesc)))
+	})
+	return file_golw_proto_rawDescData
+}
+
+var file_golw_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_golw_proto_goTypes = []any{
+	(*Cell)(nil),           // 0: golw.v1.Cell
+	(*Rectangle)(nil),      // 1: golw.v1.Rectangle
+	(*Author)(nil),         // 2: golw.v1.Author
+	(*EditRequest)(nil),    // 3: golw.v1.EditRequest
+	(*EditReply)(nil),      // 4: golw.v1.EditReply
+	(*SyncRequest)(nil),    // 5: golw.v1.SyncRequest
+	(*SyncReply)(nil),      // 6: golw.v1.SyncReply
+	(*Stats)(nil),          // 7: golw.v1.Stats
+	(*Topology)(nil),       // 8: golw.v1.Topology
+	(*ObserveRequest)(nil), // 9: golw.v1.ObserveRequest
+	(*ObserveEvent)(nil),   // 10: golw.v1.ObserveEvent
+	(*Tick)(nil),           // 11: golw.v1.Tick
+	(*CellEdit)(nil),       // 12: golw.v1.CellEdit
+	(*Periodic)(nil),       // 13: golw.v1.Periodic
+}
+var file_golw_proto_depIdxs = []int32{
+	0,  // 0: golw.v1.Rectangle.min:type_name -> golw.v1.Cell
+	0,  // 1: golw.v1.Rectangle.max:type_name -> golw.v1.Cell
+	0,  // 2: golw.v1.EditRequest.cells:type_name -> golw.v1.Cell
+	1,  // 3: golw.v1.SyncRequest.bounds:type_name -> golw.v1.Rectangle
+	0,  // 4: golw.v1.SyncReply.cells:type_name -> golw.v1.Cell
+	7,  // 5: golw.v1.SyncReply.stats:type_name -> golw.v1.Stats
+	8,  // 6: golw.v1.SyncReply.topology:type_name -> golw.v1.Topology
+	1,  // 7: golw.v1.Stats.bounds:type_name -> golw.v1.Rectangle
+	1,  // 8: golw.v1.Topology.bounds:type_name -> golw.v1.Rectangle
+	1,  // 9: golw.v1.ObserveRequest.bounds:type_name -> golw.v1.Rectangle
+	11, // 10: golw.v1.ObserveEvent.tick:type_name -> golw.v1.Tick
+	12, // 11: golw.v1.ObserveEvent.set_cell:type_name -> golw.v1.CellEdit
+	12, // 12: golw.v1.ObserveEvent.clear_cell:type_name -> golw.v1.CellEdit
+	13, // 13: golw.v1.ObserveEvent.periodic:type_name -> golw.v1.Periodic
+	0,  // 14: golw.v1.Tick.born:type_name -> golw.v1.Cell
+	0,  // 15: golw.v1.Tick.died:type_name -> golw.v1.Cell
+	0,  // 16: golw.v1.CellEdit.cell:type_name -> golw.v1.Cell
+	2,  // 17: golw.v1.CellEdit.author:type_name -> golw.v1.Author
+	1,  // 18: golw.v1.Periodic.region:type_name -> golw.v1.Rectangle
+	3,  // 19: golw.v1.Game.SetCells:input_type -> golw.v1.EditRequest
+	3,  // 20: golw.v1.Game.ClearCells:input_type -> golw.v1.EditRequest
+	5,  // 21: golw.v1.Game.Sync:input_type -> golw.v1.SyncRequest
+	9,  // 22: golw.v1.Game.Observe:input_type -> golw.v1.ObserveRequest
+	4,  // 23: golw.v1.Game.SetCells:output_type -> golw.v1.EditReply
+	4,  // 24: golw.v1.Game.ClearCells:output_type -> golw.v1.EditReply
+	6,  // 25: golw.v1.Game.Sync:output_type -> golw.v1.SyncReply
+	10, // 26: golw.v1.Game.Observe:output_type -> golw.v1.ObserveEvent
+	23, // [23:27] is the sub-list for method output_type
+	19, // [19:23] is the sub-list for method input_type
+	19, // [19:19] is the sub-list for extension type_name
+	19, // [19:19] is the sub-list for extension extendee
+	0,  // [0:19] is the sub-list for field type_name
+}
+
+func init() { file_golw_proto_init() }
+func file_golw_proto_init() {
+	if File_golw_proto != nil {
+		return
+	}
+	file_golw_proto_msgTypes[10].OneofWrappers = []any{
+		(*ObserveEvent_Tick)(nil),
+		(*ObserveEvent_SetCell)(nil),
+		(*ObserveEvent_ClearCell)(nil),
+		(*ObserveEvent_Periodic)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_golw_proto_rawDesc), len(file_golw_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_golw_proto_goTypes,
+		DependencyIndexes: file_golw_proto_depIdxs,
+		MessageInfos:      file_golw_proto_msgTypes,
+	}.Build()
+	File_golw_proto = out.File
+	file_golw_proto_goTypes = nil
+	file_golw_proto_depIdxs = nil
+}
@@ -0,0 +1,257 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: golw.proto
+
+package rpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Game_SetCells_FullMethodName   = "/golw.v1.Game/SetCells"
+	Game_ClearCells_FullMethodName = "/golw.v1.Game/ClearCells"
+	Game_Sync_FullMethodName       = "/golw.v1.Game/Sync"
+	Game_Observe_FullMethodName    = "/golw.v1.Game/Observe"
+)
+
+// GameClient is the client API for Game service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Game edits and observes the world for backend integrations. Its methods
+// mirror the WebSocket commands of the same names.
+type GameClient interface {
+	// SetCells brings cells to life.
+	SetCells(ctx context.Context, in *EditRequest, opts ...grpc.CallOption) (*EditReply, error)
+	// ClearCells kills cells.
+	ClearCells(ctx context.Context, in *EditRequest, opts ...grpc.CallOption) (*EditReply, error)
+	// Sync returns the live cells inside bounds along with the game's stats
+	// and topology. Its size is limited like the observe command's.
+	Sync(ctx context.Context, in *SyncRequest, opts ...grpc.CallOption) (*SyncReply, error)
+	// Observe streams the changes inside bounds until the call is cancelled.
+	// Its size is limited like the observe command's.
+	Observe(ctx context.Context, in *ObserveRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ObserveEvent], error)
+}
+
+type gameClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGameClient(cc grpc.ClientConnInterface) GameClient {
+	return &gameClient{cc}
+}
+
+func (c *gameClient) SetCells(ctx context.Context, in *EditRequest, opts ...grpc.CallOption) (*EditReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EditReply)
+	err := c.cc.Invoke(ctx, Game_SetCells_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gameClient) ClearCells(ctx context.Context, in *EditRequest, opts ...grpc.CallOption) (*EditReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EditReply)
+	err := c.cc.Invoke(ctx, Game_ClearCells_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gameClient) Sync(ctx context.Context, in *SyncRequest, opts ...grpc.CallOption) (*SyncReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SyncReply)
+	err := c.cc.Invoke(ctx, Game_Sync_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gameClient) Observe(ctx context.Context, in *ObserveRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ObserveEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Game_ServiceDesc.Streams[0], Game_Observe_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ObserveRequest, ObserveEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Game_ObserveClient = grpc.ServerStreamingClient[ObserveEvent]
+
+// GameServer is the server API for Game service.
+// All implementations must embed UnimplementedGameServer
+// for forward compatibility.
+//
+// Game edits and observes the world for backend integrations. Its methods
+// mirror the WebSocket commands of the same names.
+type GameServer interface {
+	// SetCells brings cells to life.
+	SetCells(context.Context, *EditRequest) (*EditReply, error)
+	// ClearCells kills cells.
+	ClearCells(context.Context, *EditRequest) (*EditReply, error)
+	// Sync returns the live cells inside bounds along with the game's stats
+	// and topology. Its size is limited like the observe command's.
+	Sync(context.Context, *SyncRequest) (*SyncReply, error)
+	// Observe streams the changes inside bounds until the call is cancelled.
+	// Its size is limited like the observe command's.
+	Observe(*ObserveRequest, grpc.ServerStreamingServer[ObserveEvent]) error
+	mustEmbedUnimplementedGameServer()
+}
+
+// UnimplementedGameServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedGameServer struct{}
+
+func (UnimplementedGameServer) SetCells(context.Context, *EditRequest) (*EditReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetCells not implemented")
+}
+func (UnimplementedGameServer) ClearCells(context.Context, *EditRequest) (*EditReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClearCells not implemented")
+}
+func (UnimplementedGameServer) Sync(context.Context, *SyncRequest) (*SyncReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Sync not implemented")
+}
+func (UnimplementedGameServer) Observe(*ObserveRequest, grpc.ServerStreamingServer[ObserveEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method Observe not implemented")
+}
+func (UnimplementedGameServer) mustEmbedUnimplementedGameServer() {}
+func (UnimplementedGameServer) testEmbeddedByValue()              {}
+
+// UnsafeGameServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GameServer will
+// result in compilation errors.
+type UnsafeGameServer interface {
+	mustEmbedUnimplementedGameServer()
+}
+
+func RegisterGameServer(s grpc.ServiceRegistrar, srv GameServer) {
+	// If the following call pancis, it indicates UnimplementedGameServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Game_ServiceDesc, srv)
+}
+
+func _Game_SetCells_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EditRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GameServer).SetCells(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Game_SetCells_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GameServer).SetCells(ctx, req.(*EditRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Game_ClearCells_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EditRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GameServer).ClearCells(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Game_ClearCells_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GameServer).ClearCells(ctx, req.(*EditRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Game_Sync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SyncRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GameServer).Sync(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Game_Sync_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GameServer).Sync(ctx, req.(*SyncRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Game_Observe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ObserveRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GameServer).Observe(m, &grpc.GenericServerStream[ObserveRequest, ObserveEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Game_ObserveServer = grpc.ServerStreamingServer[ObserveEvent]
+
+// Game_ServiceDesc is the grpc.ServiceDesc for Game service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Game_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "golw.v1.Game",
+	HandlerType: (*GameServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SetCells",
+			Handler:    _Game_SetCells_Handler,
+		},
+		{
+			MethodName: "ClearCells",
+			Handler:    _Game_ClearCells_Handler,
+		},
+		{
+			MethodName: "Sync",
+			Handler:    _Game_Sync_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Observe",
+			Handler:       _Game_Observe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "golw.proto",
+}
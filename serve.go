@@ -0,0 +1,193 @@
+package main
+
+import (
+	"flag"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/henilmalaviya/gol/grid"
+	"github.com/henilmalaviya/golw/env"
+	"github.com/henilmalaviya/golw/game"
+	"github.com/henilmalaviya/golw/server"
+	"github.com/henilmalaviya/golw/util"
+	"github.com/henilmalaviya/golw/web"
+)
+
+func loadTopology() game.Topology {
+	logger := util.GetLogger()
+
+	kind, err := game.ParseTopologyKind(env.Get().WorldTopology)
+	if err != nil {
+		logger.Fatal("Invalid world topology", "error", err)
+	}
+
+	bounds, err := util.ParseRectangle(env.Get().WorldBounds)
+	if kind != game.TopologyInfinite && err != nil {
+		logger.Fatal("Invalid world bounds", "error", err)
+	}
+
+	topology, err := game.NewTopology(kind, bounds)
+	if err != nil {
+		logger.Fatal("Invalid world topology", "error", err)
+	}
+
+	logger.Info("World topology configured", "kind", topology.Kind, "bounds", topology.Bounds.ToNestedArray())
+	return topology
+}
+
+func loadPopulationLimits() game.PopulationLimits {
+	logger := util.GetLogger()
+
+	action, err := game.ParsePopulationAction(env.Get().PopulationCapAction)
+	if err != nil {
+		logger.Fatal("Invalid population cap action", "error", err)
+	}
+
+	cullBounds, err := util.ParseRectangle(env.Get().PopulationCullBounds)
+	if action == game.PopulationActionCull && err != nil {
+		logger.Fatal("Invalid population cull bounds", "error", err)
+	}
+
+	return game.PopulationLimits{
+		SoftCap:    env.Get().PopulationSoftCap,
+		HardCap:    env.Get().PopulationHardCap,
+		Action:     action,
+		CullBounds: cullBounds,
+	}
+}
+
+// reloadOnHangup reloads the settings whenever the process receives SIGHUP
+// and applies the ones that can change at runtime.
+func reloadOnHangup(gm *game.Manager) {
+	logger := util.GetLogger()
+
+	hangups := make(chan os.Signal, 1)
+	signal.Notify(hangups, syscall.SIGHUP)
+
+	for range hangups {
+		changed, needRestart, err := env.Reload()
+		if err != nil {
+			logger.Error("Failed to reload configuration, keeping the current settings", "error", err)
+			continue
+		}
+		if len(needRestart) > 0 {
+			logger.Warn("Some settings changed but need a restart to take effect", "settings", needRestart)
+		}
+		if len(changed) == 0 {
+			logger.Info("Configuration reloaded, nothing changed")
+			continue
+		}
+
+		util.SetLogLevel(env.Get().LogLevel)
+		if slices.Contains(changed, "TICK_SPEED") {
+			gm.SetTickInterval(time.Millisecond * time.Duration(env.Get().TickSpeed))
+		}
+		server.ApplyRateLimits()
+		logger.Info("Configuration reloaded", "changed", changed)
+	}
+}
+
+// runServe runs the game server until it fails.
+func runServe(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	flags.Usage = usageFor(flags, "serve [flags]", "Run the game server.")
+	applySettings := settingFlags(flags, env.Keys()...)
+	flags.Parse(args)
+	configErr := applySettings()
+
+	logger := util.GetLogger()
+	if configErr != nil {
+		logger.Fatal("Invalid configuration", "error", configErr)
+	}
+	logger.Info("Starting Game of Life WebSocket server", "log_level", env.Get().LogLevel)
+	if path := env.ConfigFile(); path != "" {
+		logger.Info("Loaded config file", "path", path)
+	}
+
+	gm := game.NewManager()
+	gm.SetTickWorkers(env.Get().TickWorkers)
+	gm.SetTopology(loadTopology())
+	gm.SetPopulationLimits(loadPopulationLimits())
+	gm.SetJournalLimits(env.Get().UndoHistory, env.Get().UndoMaxGenerations)
+	gm.SetPeriodWindow(env.Get().PeriodWindow)
+	gm.SetHistorySize(env.Get().StatsHistorySize)
+
+	// The policy was validated with the other settings above.
+	tickPolicy, _ := game.ParseTickPolicy(env.Get().TickOverrunPolicy)
+	gm.SetTickPolicy(tickPolicy, env.Get().TickMaxCatchUp)
+	logger.Info("Game manager initialized")
+
+	saveManager := game.NewSaveManager(gm)
+
+	if err := saveManager.LoadLatest(); err != nil {
+		logger.Error("Failed to load latest snapshot", "error", err)
+		// load a blinker pattern to start with
+		gm.EditCells([]grid.Cell{{X: 0, Y: 1}, {X: 0, Y: 0}, {X: 0, Y: -1}}, true, game.Author{})
+
+		logger.Info("Initialized game with default blinker pattern")
+
+	} else {
+		logger.Info("Loaded latest snapshot successfully")
+		logger.Info("Current game stats", "stats", gm.GetStats())
+	}
+
+	saveManager.StartSaving()
+
+	gm.Start(time.Millisecond * time.Duration(env.Get().TickSpeed))
+	logger.Info("Game tick started", "interval", env.Get().TickSpeed, "policy", tickPolicy)
+
+	gm.StartCensus(time.Second * time.Duration(env.Get().CensusInterval))
+
+	go reloadOnHangup(gm)
+
+	http.HandleFunc(env.Get().WSEndpoint, server.WebsocketHandler(gm))
+	logger.Info("WebSocket endpoint registered", "endpoint", env.Get().WSEndpoint)
+
+	if env.Get().HTTPTransport {
+		http.HandleFunc("/api/events", server.EventStreamHandler(gm))
+		http.HandleFunc("/api/poll", server.PollHandler(gm))
+		http.HandleFunc("/api/command", server.PostCommandHandler)
+		logger.Info("HTTP transport enabled", "events", "/api/events", "poll", "/api/poll", "command", "/api/command")
+	}
+
+	http.HandleFunc("/api/random_fill", server.RandomFillHandler(gm))
+	http.HandleFunc("/api/census", server.CensusHandler(gm))
+	http.HandleFunc("/api/stats_history", server.StatsHistoryHandler(gm))
+
+	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("OK"))
+	})
+
+	if port := env.Get().GRPCPort; port != "" {
+		listener, err := net.Listen("tcp", ":"+port)
+		if err != nil {
+			return err
+		}
+		go func() {
+			if err := server.NewGRPCServer(gm).Serve(listener); err != nil {
+				logger.Error("gRPC server stopped", "error", err)
+			}
+		}()
+		logger.Info("gRPC API enabled", "port", port)
+	}
+
+	if env.Get().WebViewer {
+		viewer, err := web.Handler(web.Config{
+			WSEndpoint:    env.Get().WSEndpoint,
+			MaxRegionSize: env.Get().MaxObserveRegionSize,
+		})
+		if err != nil {
+			return err
+		}
+		http.Handle("/", viewer)
+		logger.Info("Web viewer enabled", "path", "/")
+	}
+
+	logger.Info("Starting HTTP server", "port", env.Get().Port)
+	return http.ListenAndServe(":"+env.Get().Port, nil)
+}
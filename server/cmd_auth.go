@@ -0,0 +1,160 @@
+package server
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/henilmalaviya/gol/grid"
+	"github.com/henilmalaviya/golw/env"
+	"github.com/henilmalaviya/golw/game"
+	"github.com/henilmalaviya/golw/util"
+	"github.com/tidwall/gjson"
+)
+
+const MaxNameLength = 32
+
+var colorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// checkAdminToken compares token with the configured admin token.
+func checkAdminToken(token string) error {
+	adminToken := env.Get().AdminToken
+	if adminToken == "" {
+		return fmt.Errorf("admin access is disabled")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1 {
+		return fmt.Errorf("invalid token")
+	}
+	return nil
+}
+
+// checkUserToken compares token with the token of the registered user name.
+func checkUserToken(name, token string) error {
+	want, ok := userToken(name)
+	if !ok {
+		return fmt.Errorf("%s is not a registered user", name)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(token), []byte(want)) != 1 {
+		return fmt.Errorf("invalid token")
+	}
+	return nil
+}
+
+// userToken returns the token of a registered user.
+func userToken(name string) (string, bool) {
+	tokens, err := env.ParseUserTokens(env.Get().UserTokens)
+	if err != nil {
+		return "", false
+	}
+	token, ok := tokens[name]
+	return token, ok
+}
+
+func authenticateAdmin(token string, observer *Observer) error {
+	if err := checkAdminToken(token); err != nil {
+		return err
+	}
+
+	observer.stateMutex.Lock()
+	defer observer.stateMutex.Unlock()
+
+	// As in watch, nothing is added once the observer is closed.
+	if observer.alertObserver == nil && observer.Context().Err() == nil {
+		observer.alertObserver = grid.NewGlobalObserver(func(event grid.ObserverEvent) {
+			if e, ok := event.(game.PopulationAlertEvent); ok {
+				observer.SendOutgoingMessage(NewOutgoingMessage(CodeAlert, MessageData{
+					"event": e.Type(),
+					"data":  e,
+				}))
+			}
+		})
+		observer.Manager.AddObserver(observer.alertObserver)
+	}
+	observer.admin = true
+
+	return nil
+}
+
+func CommandAuthHandler(data gjson.Result, observer *Observer, wc chan<- OutgoingMessage) {
+	logger := util.GetLogger()
+
+	name := data.Get("name")
+	color := data.Get("color")
+	token := data.Get("token")
+
+	if !name.Exists() && !color.Exists() && !token.Exists() {
+		logger.Warn("Auth command received without name, color or token")
+		wc <- NewOutgoingErrorMessage("name, color or token required")
+		return
+	}
+
+	current := observer.Author()
+	newName := current.Name
+	if name.Exists() {
+		newName = strings.TrimSpace(name.String())
+		if newName == "" || len(newName) > MaxNameLength {
+			logger.Warn("Invalid name received in auth command", "length", len(newName))
+			wc <- NewOutgoingErrorMessage(fmt.Sprintf("name must be between 1 and %d characters", MaxNameLength))
+			return
+		}
+	}
+
+	if color.Exists() && !colorPattern.MatchString(color.String()) {
+		logger.Warn("Invalid color received in auth command", "color", color.String())
+		wc <- NewOutgoingErrorMessage("color must have the form #rrggbb")
+		return
+	}
+
+	// A token proves either that the client is the registered user of the
+	// name or that it has admin access. A registered name can't be taken
+	// without its token, and changing the name drops the verified user.
+	user := current.User
+	if name.Exists() && newName != current.User {
+		user = ""
+	}
+	_, registered := userToken(newName)
+
+	if token.Exists() {
+		if registered && checkUserToken(newName, token.String()) == nil {
+			user = newName
+			logger.Info("Client authenticated as user", "id", observer.ID, "user", user)
+		} else {
+			if err := authenticateAdmin(token.String(), observer); err != nil {
+				logger.Warn("Authentication failed", "id", observer.ID, "name", newName, "error", err)
+				wc <- NewOutgoingErrorMessage(err.Error())
+				return
+			}
+			logger.Info("Client authenticated as admin", "id", observer.ID)
+		}
+	} else if name.Exists() && registered && user != newName && !observer.IsAdmin() {
+		logger.Warn("Registered name claimed without its token", "id", observer.ID, "name", newName)
+		wc <- NewOutgoingErrorMessage(fmt.Sprintf("%s is a registered user, send its token", newName))
+		return
+	}
+
+	if name.Exists() {
+		observer.SetName(newName)
+	}
+	observer.SetUser(user)
+	if color.Exists() {
+		observer.SetColor(color.String())
+	}
+
+	presence.Broadcast(PresenceUpdate, observer)
+
+	author := observer.Author()
+	wc <- NewOutgoingMessage(CodeAuthOk, MessageData{
+		"id":    author.ID,
+		"name":  author.Name,
+		"color": author.Color,
+		"user":  author.User,
+		"admin": observer.IsAdmin(),
+	})
+}
+
+func init() {
+	registry.Register(CommandAuth, CommandAuthHandler)
+}
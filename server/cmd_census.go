@@ -0,0 +1,27 @@
+package server
+
+import (
+	"github.com/henilmalaviya/golw/game"
+	"github.com/tidwall/gjson"
+)
+
+// latestCensus returns the periodic census, taking one if there is none yet
+// or refresh is set.
+func latestCensus(gm *game.Manager, refresh bool) game.Census {
+	if census := gm.LastCensus(); census != nil && !refresh {
+		return *census
+	}
+	return gm.RunCensus()
+}
+
+func CommandCensusHandler(data gjson.Result, observer *Observer, wc chan<- OutgoingMessage) {
+	// Only admins may force a new census, as it walks the whole world.
+	refresh := data.Get("refresh").Bool() && observer.IsAdmin()
+
+	census := latestCensus(observer.Manager, refresh)
+	wc <- NewOutgoingMessage(CodeCensusOk, MessageData{"census": census})
+}
+
+func init() {
+	registry.Register(CommandCensus, CommandCensusHandler)
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/henilmalaviya/golw/env"
+	"github.com/henilmalaviya/golw/util"
+	"github.com/tidwall/gjson"
+)
+
+func CommandChatHandler(data gjson.Result, observer *Observer, wc chan<- OutgoingMessage) {
+	logger := util.GetLogger()
+
+	text := strings.TrimSpace(data.Get("text").String())
+	if text == "" {
+		logger.Warn("Empty chat message received")
+		wc <- NewOutgoingErrorMessage("text required")
+		return
+	}
+
+	maxLength := env.Get().MaxChatLength
+	if utf8.RuneCountInString(text) > maxLength {
+		logger.Warn("Chat message too long", "id", observer.ID, "length", utf8.RuneCountInString(text))
+		wc <- NewOutgoingErrorMessage(fmt.Sprintf("text exceeds maximum length (%d)", maxLength))
+		return
+	}
+
+	if !observer.chatLimiter.Allow() {
+		logger.Warn("Chat rate limit exceeded", "id", observer.ID)
+		wc <- ErrorRateLimited
+		return
+	}
+
+	msg := NewOutgoingMessage(CodeChatEvent, MessageData{
+		"user": observer.Author(),
+		"text": text,
+		"time": time.Now().UTC(),
+	})
+
+	for _, o := range presence.Observers() {
+		if o == observer {
+			continue
+		}
+		o.SendOutgoingMessage(msg)
+	}
+
+	logger.Debug("Chat message broadcast", "id", observer.ID, "length", len(text))
+	wc <- OkMessage
+}
+
+func init() {
+	registry.Register(CommandChat, CommandChatHandler)
+}
@@ -15,14 +15,23 @@ func CommandClearCellsHandler(data gjson.Result, observer *Observer, wc chan<- O
 		return
 	}
 
-	gm := observer.Manager.GetGame()
+	if err := checkEdit(observer, cellsArray, nil); err != nil {
+		logger.Warn("Rejected clear_cells", "id", observer.ID, "error", err)
+		wc <- NewOutgoingErrorMessage(err.Error())
+		return
+	}
+
+	gm := observer.Manager
 	logger.Info("Clearing cells", "count", len(cellsArray))
 
-	for _, cell := range cellsArray {
-		gm.GetGrid().ClearCell(cell.X, cell.Y)
+	result, err := gm.EditCells(cellsArray, false, observer.Author())
+	if err != nil {
+		logger.Warn("Rejected clear_cells", "id", observer.ID, "error", err)
+		wc <- NewOutgoingErrorMessage(err.Error())
+		return
 	}
 
-	wc <- OkMessage
+	wc <- NewEditOkMessage(result)
 }
 
 func init() {
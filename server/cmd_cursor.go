@@ -0,0 +1,42 @@
+package server
+
+import (
+	"github.com/henilmalaviya/golw/util"
+	"github.com/tidwall/gjson"
+)
+
+// CommandCursorHandler forwards the client's cursor position to the other
+// clients observing it. Cursor updates are frequent, so successful updates
+// get no reply and throttled ones are dropped silently.
+func CommandCursorHandler(data gjson.Result, observer *Observer, wc chan<- OutgoingMessage) {
+	logger := util.GetLogger()
+
+	x, y := data.Get("x"), data.Get("y")
+	if x.Type != gjson.Number || y.Type != gjson.Number {
+		logger.Warn("Invalid cursor data received")
+		wc <- NewOutgoingErrorMessage("invalid cursor data")
+		return
+	}
+
+	if !observer.cursorLimiter.Allow() {
+		return
+	}
+
+	cx, cy := int(x.Int()), int(y.Int())
+	msg := NewOutgoingMessage(CodeCursorEvent, MessageData{
+		"user": observer.Author(),
+		"x":    cx,
+		"y":    cy,
+	})
+
+	for _, o := range presence.Observers() {
+		if o == observer || !o.IsObserving(cx, cy) {
+			continue
+		}
+		o.SendOutgoingMessage(msg)
+	}
+}
+
+func init() {
+	registry.Register(CommandCursor, CommandCursorHandler)
+}
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/henilmalaviya/golw/util"
+	"github.com/tidwall/gjson"
+)
+
+const (
+	DefaultLocateLimit = 10
+	MaxLocateLimit     = 100
+)
+
+func CommandLocateHandler(data gjson.Result, observer *Observer, wc chan<- OutgoingMessage) {
+	logger := util.GetLogger()
+
+	limit := DefaultLocateLimit
+	if l := data.Get("limit"); l.Exists() {
+		limit = int(l.Int())
+		if limit < 1 || limit > MaxLocateLimit {
+			logger.Warn("Invalid limit received in locate command", "limit", l.Raw)
+			wc <- NewOutgoingErrorMessage(fmt.Sprintf("limit must be between 1 and %d", MaxLocateLimit))
+			return
+		}
+	}
+
+	clusters := observer.Manager.Locate(limit)
+	stats := observer.Manager.GetStats()
+
+	wc <- NewOutgoingMessage(CodeLocateOk, MessageData{
+		"clusters":   clusters,
+		"bounds":     stats.Bounds,
+		"population": stats.Population,
+	})
+}
+
+func init() {
+	registry.Register(CommandLocate, CommandLocateHandler)
+}
@@ -2,9 +2,11 @@ package server
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/henilmalaviya/gol/grid"
 	"github.com/henilmalaviya/golw/env"
+	"github.com/henilmalaviya/golw/game"
 	"github.com/henilmalaviya/golw/util"
 	"github.com/tidwall/gjson"
 )
@@ -17,14 +19,19 @@ func cellSliceToIntSlice(cells []grid.Cell) [][]int {
 	return parsedCells
 }
 
+func editEventData(cell grid.Cell, author game.Author) MessageData {
+	data := MessageData{
+		"cell": [2]int{cell.X, cell.Y},
+	}
+	if !author.IsZero() {
+		data["author"] = author
+	}
+	return data
+}
+
 func CommandObserveHandler(data gjson.Result, observer *Observer, wc chan<- OutgoingMessage) {
 	logger := util.GetLogger()
 
-	gm := observer.Manager.GetGame()
-	gr := gm.GetGrid()
-
-	var obs *grid.RegionObserver
-
 	bounds, ok := util.GetBoundsFromData(data, "bounds")
 	if !ok {
 		logger.Warn("Invalid bounds data received in observe command")
@@ -47,68 +54,90 @@ func CommandObserveHandler(data gjson.Result, observer *Observer, wc chan<- Outg
 
 	logger.Debug("Setting up region observer", "width", bounds.Width(), "height", bounds.Height())
 
+	// The manager calls updateFunc with its lock held, so sending must never
+	// block. A client too far behind to take the events has its session
+	// closed, which ends the observation once the connection is torn down.
+	var behind sync.Once
+	send := func(msg OutgoingMessage) {
+		select {
+		case wc <- msg:
+		default:
+			if observer.Context().Err() != nil {
+				return
+			}
+			behind.Do(func() {
+				logger.Warn("Observer fell behind, closing session", "id", observer.ID)
+				observer.Session.Close()
+			})
+		}
+	}
+
 	var updateFunc func(event grid.ObserverEvent) = func(event grid.ObserverEvent) {
 		switch e := event.(type) {
-		case grid.SetCellsObserverEvent:
-			cells := e.Data()
-
-			parsedCells := cellSliceToIntSlice(cells)
-
-			wc <- NewOutgoingMessage(CodeObserveEvent, MessageData{
+		case game.SetCellEvent:
+			send(NewOutgoingMessage(CodeObserveEvent, MessageData{
 				"event": e.Type(),
-				"data": map[string][][]int{
-					"cells": parsedCells,
-				},
-			})
-		case grid.SetCellObserverEvent:
-			cell := e.Data()
-
-			wc <- NewOutgoingMessage(CodeObserveEvent, MessageData{
+				"data":  editEventData(e.Cell, e.Author),
+			}))
+		case game.ClearCellEvent:
+			send(NewOutgoingMessage(CodeObserveEvent, MessageData{
 				"event": e.Type(),
-				"data": map[string][2]int{
-					"cell": {cell.X, cell.Y},
-				},
-			})
-		case grid.ClearCellObserverEvent:
-			cell := e.Data()
-
-			wc <- NewOutgoingMessage(CodeObserveEvent, MessageData{
+				"data":  editEventData(e.Cell, e.Author),
+			}))
+		case game.PeriodEvent:
+			data := MessageData{
+				"generation":    e.Generation,
+				"period":        e.Period,
+				"stabilized_at": e.StabilizedAt,
+			}
+			if e.Region != nil {
+				data["region"] = e.Region.ToNestedArray()
+			}
+			send(NewOutgoingMessage(CodeObserveEvent, MessageData{
 				"event": e.Type(),
-				"data": map[string][2]int{
-					"cell": {cell.X, cell.Y},
-				},
-			})
-		case grid.TickObserverEvent:
-			bornCells, diedCells := e.Data()
-
-			if len(bornCells) == 0 && len(diedCells) == 0 {
+				"data":  data,
+			}))
+		case game.TickEvent:
+			if len(e.BornCells) == 0 && len(e.DiedCells) == 0 {
 				return
 			}
 
-			parsedBornCells := cellSliceToIntSlice(bornCells)
-			parsedDiedCells := cellSliceToIntSlice(diedCells)
+			parsedBornCells := cellSliceToIntSlice(e.BornCells)
+			parsedDiedCells := cellSliceToIntSlice(e.DiedCells)
 
-			wc <- NewOutgoingMessage(CodeObserveEvent, MessageData{
+			send(NewOutgoingMessage(CodeObserveEvent, MessageData{
 				"event": e.Type(),
 				"data": map[string][][]int{
 					"bornCells": parsedBornCells,
 					"diedCells": parsedDiedCells,
 				},
-			})
+			}))
 		}
 	}
 
-	if observer.gridObserver != nil {
-		observer.gridObserver.SetRegion(bounds)
-	} else {
-		obs = grid.NewRegionObserver(bounds, updateFunc)
-		observer.gridObserver = obs
-		gr.AddObserver(observer.gridObserver)
+	// Period detection for the region is opt in, as it runs on every tick.
+	periodWindow := 0
+	if data.Get("detect_period").Bool() {
+		periodWindow = env.Get().PeriodWindow
+	}
+
+	done, ok := observer.watch(bounds, periodWindow, updateFunc)
+	if !ok {
+		return
 	}
 
+	presence.Broadcast(PresenceUpdate, observer)
+
 	wc <- NewOutgoingMessage(CodeObserveOk, MessageData{
 		"bounds": bounds.ToNestedArray(),
 	})
+
+	// The events go to wc until the client stops observing or leaves. An
+	// observe that only moved the region is done, as the events still go to
+	// the first one.
+	if done != nil {
+		<-done
+	}
 }
 
 func init() {
@@ -0,0 +1,41 @@
+package server
+
+import (
+	"github.com/henilmalaviya/golw/util"
+	"github.com/tidwall/gjson"
+)
+
+func CommandPauseHandler(data gjson.Result, observer *Observer, wc chan<- OutgoingMessage) {
+	logger := util.GetLogger()
+
+	if !observer.IsAdmin() {
+		logger.Warn("Pause command received from non-admin client")
+		wc <- ErrorAdminRequired
+		return
+	}
+
+	observer.Manager.Pause()
+
+	logger.Info("Game paused by admin")
+	wc <- OkMessage
+}
+
+func CommandResumeHandler(data gjson.Result, observer *Observer, wc chan<- OutgoingMessage) {
+	logger := util.GetLogger()
+
+	if !observer.IsAdmin() {
+		logger.Warn("Resume command received from non-admin client")
+		wc <- ErrorAdminRequired
+		return
+	}
+
+	observer.Manager.Resume()
+
+	logger.Info("Game resumed by admin")
+	wc <- OkMessage
+}
+
+func init() {
+	registry.Register(CommandPause, CommandPauseHandler)
+	registry.Register(CommandResume, CommandResumeHandler)
+}
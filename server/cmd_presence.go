@@ -0,0 +1,28 @@
+package server
+
+import (
+	"github.com/henilmalaviya/golw/util"
+	"github.com/tidwall/gjson"
+)
+
+func CommandPresenceHandler(data gjson.Result, observer *Observer, wc chan<- OutgoingMessage) {
+	logger := util.GetLogger()
+
+	subscribe := true
+	if sub := data.Get("subscribe"); sub.Exists() {
+		subscribe = sub.Bool()
+	}
+	observer.SetPresenceSubscribed(subscribe)
+
+	users := presence.List()
+	logger.Debug("Listing presence", "id", observer.ID, "users", len(users), "subscribe", subscribe)
+
+	wc <- NewOutgoingMessage(CodePresenceOk, MessageData{
+		"self":  observer.ID,
+		"users": users,
+	})
+}
+
+func init() {
+	registry.Register(CommandPresence, CommandPresenceHandler)
+}
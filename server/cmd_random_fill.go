@@ -0,0 +1,73 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/henilmalaviya/golw/game"
+	"github.com/henilmalaviya/golw/util"
+	"github.com/tidwall/gjson"
+)
+
+// parseRandomFill reads {bounds, density, seed} from data. A missing seed is
+// replaced with a random one, which the reply reports.
+func parseRandomFill(data gjson.Result) (game.RandomFill, error) {
+	bounds, ok := util.GetBoundsFromData(data, "bounds")
+	if !ok {
+		return game.RandomFill{}, fmt.Errorf("invalid bounds data")
+	}
+	if err := checkRegionSize(bounds); err != nil {
+		return game.RandomFill{}, err
+	}
+
+	density := data.Get("density")
+	if density.Type != gjson.Number {
+		return game.RandomFill{}, fmt.Errorf("invalid density")
+	}
+
+	fill := game.RandomFill{
+		Bounds:  *bounds.Normalized(),
+		Density: density.Float(),
+		Seed:    game.NewRandomSeed(),
+	}
+
+	if seed := data.Get("seed"); seed.Exists() {
+		if seed.Type != gjson.Number {
+			return game.RandomFill{}, fmt.Errorf("invalid seed")
+		}
+		fill.Seed = seed.Int()
+	}
+
+	return fill, fill.Validate()
+}
+
+func CommandRandomFillHandler(data gjson.Result, observer *Observer, wc chan<- OutgoingMessage) {
+	logger := util.GetLogger()
+
+	fill, err := parseRandomFill(data)
+	if err != nil {
+		logger.Warn("Invalid random_fill command", "id", observer.ID, "error", err)
+		wc <- NewOutgoingErrorMessage(err.Error())
+		return
+	}
+
+	if err := checkEdit(observer, nil, &fill.Bounds); err != nil {
+		logger.Warn("Rejected random_fill", "id", observer.ID, "error", err)
+		wc <- NewOutgoingErrorMessage(err.Error())
+		return
+	}
+
+	result, err := observer.Manager.RandomFill(fill, observer.Author())
+	if err != nil {
+		logger.Warn("Rejected random_fill", "id", observer.ID, "error", err)
+		wc <- NewOutgoingErrorMessage(err.Error())
+		return
+	}
+
+	msg := NewEditOkMessage(result)
+	msg.Data["seed"] = fill.Seed
+	wc <- msg
+}
+
+func init() {
+	registry.Register(CommandRandomFill, CommandRandomFillHandler)
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"github.com/henilmalaviya/golw/util"
+	"github.com/tidwall/gjson"
+)
+
+func CommandReplaceRegionHandler(data gjson.Result, observer *Observer, wc chan<- OutgoingMessage) {
+	logger := util.GetLogger()
+
+	bounds, ok := util.GetBoundsFromData(data, "bounds")
+	if !ok {
+		logger.Warn("Invalid bounds data received in replace_region command")
+		wc <- NewOutgoingErrorMessage("invalid bounds data")
+		return
+	}
+
+	if err := checkRegionSize(bounds); err != nil {
+		logger.Warn("Rejected replace_region", "id", observer.ID, "error", err)
+		wc <- NewOutgoingErrorMessage(err.Error())
+		return
+	}
+
+	// An empty cells list clears the region.
+	cellsArray, _ := util.GetCellsArrayFromData(data, "cells")
+	for _, cell := range cellsArray {
+		if !cell.Inside(&bounds) {
+			logger.Warn("Cell outside bounds received in replace_region command", "x", cell.X, "y", cell.Y)
+			wc <- NewOutgoingErrorMessage("cells must be inside bounds")
+			return
+		}
+	}
+
+	if err := checkEdit(observer, cellsArray, &bounds); err != nil {
+		logger.Warn("Rejected replace_region", "id", observer.ID, "error", err)
+		wc <- NewOutgoingErrorMessage(err.Error())
+		return
+	}
+
+	logger.Info("Replacing region", "bounds", bounds.ToNestedArray(), "count", len(cellsArray))
+
+	result, err := observer.Manager.ReplaceRegion(bounds, cellsArray, observer.Author())
+	if err != nil {
+		logger.Warn("Rejected replace_region", "id", observer.ID, "error", err)
+		wc <- NewOutgoingErrorMessage(err.Error())
+		return
+	}
+
+	wc <- NewEditOkMessage(result)
+}
+
+func init() {
+	registry.Register(CommandReplaceRegion, CommandReplaceRegionHandler)
+}
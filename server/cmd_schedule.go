@@ -0,0 +1,103 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/henilmalaviya/golw/env"
+	"github.com/henilmalaviya/golw/game"
+	"github.com/henilmalaviya/golw/util"
+	"github.com/tidwall/gjson"
+)
+
+func CommandScheduleEditHandler(data gjson.Result, observer *Observer, wc chan<- OutgoingMessage) {
+	logger := util.GetLogger()
+
+	generation := data.Get("generation")
+	if generation.Type != gjson.Number {
+		logger.Warn("Invalid generation received in schedule_edit command")
+		wc <- NewOutgoingErrorMessage("invalid generation")
+		return
+	}
+
+	cellsArray, ok := util.GetCellsArrayFromData(data, "cells")
+	if !ok || len(cellsArray) == 0 {
+		logger.Warn("Invalid cells data received in schedule_edit command")
+		wc <- NewOutgoingErrorMessage("invalid cells data")
+		return
+	}
+
+	mode := game.ScheduleModeSet
+	if m := data.Get("mode").String(); m != "" {
+		parsed, err := game.ParseScheduleMode(m)
+		if err != nil {
+			logger.Warn("Invalid mode received in schedule_edit command", "mode", m)
+			wc <- NewOutgoingErrorMessage(err.Error())
+			return
+		}
+		mode = parsed
+	}
+
+	author := observer.Author()
+	if err := checkEdit(observer, cellsArray, nil); err != nil {
+		logger.Warn("Rejected schedule_edit", "id", observer.ID, "error", err)
+		wc <- NewOutgoingErrorMessage(err.Error())
+		return
+	}
+
+	// Zones are checked again when the edit runs, along with the population
+	// cap.
+	if err := observer.Manager.CheckZones(cellsArray, author); err != nil {
+		logger.Warn("Rejected schedule_edit", "id", observer.ID, "error", err)
+		wc <- NewOutgoingErrorMessage(err.Error())
+		return
+	}
+
+	if limit := env.Get().MaxScheduledEdits; limit > 0 && observer.Manager.CountScheduledEdits(author) >= limit {
+		logger.Warn("Rejected schedule_edit", "id", observer.ID, "error", "too many scheduled edits")
+		wc <- NewOutgoingErrorMessage(fmt.Sprintf("too many scheduled edits (limit is %d)", limit))
+		return
+	}
+
+	edit, err := observer.Manager.ScheduleEdit(game.ScheduledEdit{
+		Generation: int(generation.Int()),
+		Cells:      cellsArray,
+		Mode:       mode,
+		Author:     author,
+		Admin:      author.Admin,
+	})
+	if err != nil {
+		logger.Warn("Rejected schedule_edit", "id", observer.ID, "error", err)
+		wc <- NewOutgoingErrorMessage(err.Error())
+		return
+	}
+
+	logger.Info("Edit scheduled", "id", edit.ID, "generation", edit.Generation, "mode", edit.Mode, "count", len(edit.Cells))
+	wc <- NewOutgoingMessage(CodeScheduleEditOk, MessageData{"edit": edit})
+}
+
+func CommandListScheduledEditsHandler(data gjson.Result, observer *Observer, wc chan<- OutgoingMessage) {
+	edits := observer.Manager.ScheduledEdits()
+	wc <- NewOutgoingMessage(CodeListScheduledEditsOk, MessageData{"edits": edits})
+}
+
+func CommandCancelScheduledEditHandler(data gjson.Result, observer *Observer, wc chan<- OutgoingMessage) {
+	logger := util.GetLogger()
+
+	id := data.Get("id").String()
+
+	// Admins may cancel anyone's edits.
+	if err := observer.Manager.CancelScheduledEdit(id, observer.Author(), observer.IsAdmin()); err != nil {
+		logger.Warn("Failed to cancel scheduled edit", "id", id, "error", err)
+		wc <- NewOutgoingErrorMessage(err.Error())
+		return
+	}
+
+	logger.Info("Scheduled edit cancelled", "id", id)
+	wc <- OkMessage
+}
+
+func init() {
+	registry.Register(CommandScheduleEdit, CommandScheduleEditHandler)
+	registry.Register(CommandListScheduledEdits, CommandListScheduledEditsHandler)
+	registry.Register(CommandCancelScheduledEdit, CommandCancelScheduledEditHandler)
+}
@@ -15,14 +15,23 @@ func CommandSetCellsHandler(data gjson.Result, observer *Observer, wc chan<- Out
 		return
 	}
 
-	gm := observer.Manager.GetGame()
+	if err := checkEdit(observer, cellsArray, nil); err != nil {
+		logger.Warn("Rejected set_cells", "id", observer.ID, "error", err)
+		wc <- NewOutgoingErrorMessage(err.Error())
+		return
+	}
+
+	gm := observer.Manager
 	logger.Info("Setting cells", "count", len(cellsArray))
 
-	for _, cell := range cellsArray {
-		gm.GetGrid().SetCell(cell.X, cell.Y)
+	result, err := gm.EditCells(cellsArray, true, observer.Author())
+	if err != nil {
+		logger.Warn("Rejected set_cells", "id", observer.ID, "error", err)
+		wc <- NewOutgoingErrorMessage(err.Error())
+		return
 	}
 
-	wc <- OkMessage
+	wc <- NewEditOkMessage(result)
 }
 
 func init() {
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"github.com/henilmalaviya/golw/game"
+	"github.com/henilmalaviya/golw/util"
+	"github.com/tidwall/gjson"
+)
+
+func CommandStampHandler(data gjson.Result, observer *Observer, wc chan<- OutgoingMessage) {
+	logger := util.GetLogger()
+
+	cellsArray, ok := util.GetCellsArrayFromData(data, "cells")
+	if !ok {
+		logger.Warn("Invalid cells data received in stamp command")
+		wc <- NewOutgoingErrorMessage("invalid cells data")
+		return
+	}
+
+	atX, atY, ok := util.GetPointFromData(data, "at")
+	if !ok {
+		logger.Warn("Invalid at data received in stamp command")
+		wc <- NewOutgoingErrorMessage("invalid at data")
+		return
+	}
+
+	mode := game.StampModeOr
+	if m := data.Get("mode"); m.Exists() {
+		parsed, err := game.ParseStampMode(m.String())
+		if err != nil {
+			logger.Warn("Invalid mode received in stamp command", "error", err)
+			wc <- NewOutgoingErrorMessage(err.Error())
+			return
+		}
+		mode = parsed
+	}
+
+	flip, err := game.ParseFlip(data.Get("flip").String())
+	if err != nil {
+		logger.Warn("Invalid flip received in stamp command", "error", err)
+		wc <- NewOutgoingErrorMessage(err.Error())
+		return
+	}
+
+	cells, bounds, err := game.Transform(cellsArray, atX, atY, int(data.Get("rotate").Int()), flip)
+	if err != nil {
+		logger.Warn("Invalid rotation received in stamp command", "error", err)
+		wc <- NewOutgoingErrorMessage(err.Error())
+		return
+	}
+
+	if err := checkRegionSize(bounds); err != nil {
+		logger.Warn("Rejected stamp", "id", observer.ID, "error", err)
+		wc <- NewOutgoingErrorMessage(err.Error())
+		return
+	}
+
+	if err := checkEdit(observer, cells, &bounds); err != nil {
+		logger.Warn("Rejected stamp", "id", observer.ID, "error", err)
+		wc <- NewOutgoingErrorMessage(err.Error())
+		return
+	}
+
+	logger.Info("Stamping pattern", "at", [2]int{atX, atY}, "mode", mode, "count", len(cells))
+
+	result, err := observer.Manager.Stamp(cells, bounds, mode, observer.Author())
+	if err != nil {
+		logger.Warn("Rejected stamp", "id", observer.ID, "error", err)
+		wc <- NewOutgoingErrorMessage(err.Error())
+		return
+	}
+
+	wc <- NewEditOkMessage(result)
+}
+
+func init() {
+	registry.Register(CommandStamp, CommandStampHandler)
+}
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"github.com/henilmalaviya/golw/game"
+	"github.com/henilmalaviya/golw/util"
+	"github.com/tidwall/gjson"
+)
+
+// historyQuery selects a range of the stats history. It defaults to
+// everything up to the current generation at full resolution.
+type historyQuery struct {
+	From       int
+	To         int
+	Resolution int
+}
+
+func newHistoryQuery(gm *game.Manager) historyQuery {
+	return historyQuery{From: 0, To: gm.GetStats().Generation, Resolution: 1}
+}
+
+func (q historyQuery) run(gm *game.Manager) ([]game.StatsSample, error) {
+	return gm.StatsHistory().Query(q.From, q.To, q.Resolution)
+}
+
+func (q historyQuery) reply(samples []game.StatsSample) MessageData {
+	return MessageData{
+		"from":       q.From,
+		"to":         q.To,
+		"resolution": q.Resolution,
+		"samples":    samples,
+	}
+}
+
+func CommandStatsHistoryHandler(data gjson.Result, observer *Observer, wc chan<- OutgoingMessage) {
+	logger := util.GetLogger()
+
+	query := newHistoryQuery(observer.Manager)
+	if v := data.Get("from"); v.Exists() {
+		query.From = int(v.Int())
+	}
+	if v := data.Get("to"); v.Exists() {
+		query.To = int(v.Int())
+	}
+	if v := data.Get("resolution"); v.Exists() {
+		query.Resolution = int(v.Int())
+	}
+
+	samples, err := query.run(observer.Manager)
+	if err != nil {
+		logger.Warn("Invalid stats_history command", "id", observer.ID, "error", err)
+		wc <- NewOutgoingErrorMessage(err.Error())
+		return
+	}
+
+	wc <- NewOutgoingMessage(CodeStatsHistoryOk, query.reply(samples))
+}
+
+func init() {
+	registry.Register(CommandStatsHistory, CommandStatsHistoryHandler)
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/henilmalaviya/golw/game"
+	"github.com/henilmalaviya/golw/util"
+	"github.com/tidwall/gjson"
+)
+
+const (
+	MinStatsInterval = 100 * time.Millisecond
+	MaxStatsInterval = time.Hour
+)
+
+// NewStatsMessage reports the game stats, which include the tick rate and
+// population, and the number of connected users.
+func NewStatsMessage(gm *game.Manager) OutgoingMessage {
+	return NewOutgoingMessage(CodeStats, MessageData{
+		"stats": gm.GetStats(),
+		"users": presence.Count(),
+	})
+}
+
+func CommandSubscribeStatsHandler(data gjson.Result, observer *Observer, wc chan<- OutgoingMessage) {
+	logger := util.GetLogger()
+
+	// The interval is in milliseconds, zero unsubscribes.
+	ms := data.Get("interval")
+	if ms.Type != gjson.Number {
+		logger.Warn("Invalid interval received in subscribe_stats command")
+		wc <- NewOutgoingErrorMessage("invalid interval")
+		return
+	}
+
+	interval := time.Duration(ms.Int()) * time.Millisecond
+	if interval != 0 && (interval < MinStatsInterval || interval > MaxStatsInterval) {
+		logger.Warn("Interval out of range in subscribe_stats command", "interval", ms.Raw)
+		wc <- NewOutgoingErrorMessage(fmt.Sprintf("interval must be 0 or between %d and %d milliseconds", MinStatsInterval.Milliseconds(), MaxStatsInterval.Milliseconds()))
+		return
+	}
+
+	logger.Debug("Stats subscription changed", "id", observer.ID, "interval", interval)
+
+	// The stats go through wc as well, so the reply comes before the first
+	// of them.
+	wc <- NewOutgoingMessage(CodeSubscribeStatsOk, MessageData{"interval": interval.Milliseconds()})
+
+	stop := observer.SubscribeStats(interval)
+	if stop == nil {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		wc <- NewStatsMessage(observer.Manager)
+
+		select {
+		case <-stop:
+			return
+		case <-observer.Context().Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func init() {
+	registry.Register(CommandSubscribeStats, CommandSubscribeStatsHandler)
+}
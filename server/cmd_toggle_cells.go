@@ -0,0 +1,38 @@
+package server
+
+import (
+	"github.com/henilmalaviya/golw/util"
+	"github.com/tidwall/gjson"
+)
+
+func CommandToggleCellsHandler(data gjson.Result, observer *Observer, wc chan<- OutgoingMessage) {
+	logger := util.GetLogger()
+
+	cellsArray, ok := util.GetCellsArrayFromData(data, "cells")
+	if !ok {
+		logger.Warn("Invalid cells data received in toggle_cells command")
+		wc <- NewOutgoingErrorMessage("invalid cells data")
+		return
+	}
+
+	if err := checkEdit(observer, cellsArray, nil); err != nil {
+		logger.Warn("Rejected toggle_cells", "id", observer.ID, "error", err)
+		wc <- NewOutgoingErrorMessage(err.Error())
+		return
+	}
+
+	logger.Info("Toggling cells", "count", len(cellsArray))
+
+	result, err := observer.Manager.ToggleCells(cellsArray, observer.Author())
+	if err != nil {
+		logger.Warn("Rejected toggle_cells", "id", observer.ID, "error", err)
+		wc <- NewOutgoingErrorMessage(err.Error())
+		return
+	}
+
+	wc <- NewEditOkMessage(result)
+}
+
+func init() {
+	registry.Register(CommandToggleCells, CommandToggleCellsHandler)
+}
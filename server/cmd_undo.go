@@ -0,0 +1,52 @@
+package server
+
+import (
+	"github.com/henilmalaviya/gol/grid"
+	"github.com/henilmalaviya/golw/util"
+	"github.com/tidwall/gjson"
+)
+
+func CommandUndoHandler(data gjson.Result, observer *Observer, wc chan<- OutgoingMessage) {
+	logger := util.GetLogger()
+
+	result, skipped, err := observer.Manager.Undo(observer.Author(), func(cells []grid.Cell) error {
+		return checkEdit(observer, cells, nil)
+	})
+	if err != nil {
+		logger.Debug("Undo failed", "id", observer.ID, "error", err)
+		wc <- NewOutgoingErrorMessage(err.Error())
+		return
+	}
+
+	logger.Info("Undid edit", "id", observer.ID, "cells", len(result.Changes), "skipped", skipped)
+	wc <- NewOutgoingMessage(CodeUndoOk, MessageData{
+		"cells":      len(result.Changes),
+		"skipped":    skipped,
+		"generation": result.Generation,
+	})
+}
+
+func CommandRedoHandler(data gjson.Result, observer *Observer, wc chan<- OutgoingMessage) {
+	logger := util.GetLogger()
+
+	result, skipped, err := observer.Manager.Redo(observer.Author(), func(cells []grid.Cell) error {
+		return checkEdit(observer, cells, nil)
+	})
+	if err != nil {
+		logger.Debug("Redo failed", "id", observer.ID, "error", err)
+		wc <- NewOutgoingErrorMessage(err.Error())
+		return
+	}
+
+	logger.Info("Redid edit", "id", observer.ID, "cells", len(result.Changes), "skipped", skipped)
+	wc <- NewOutgoingMessage(CodeRedoOk, MessageData{
+		"cells":      len(result.Changes),
+		"skipped":    skipped,
+		"generation": result.Generation,
+	})
+}
+
+func init() {
+	registry.Register(CommandUndo, CommandUndoHandler)
+	registry.Register(CommandRedo, CommandRedoHandler)
+}
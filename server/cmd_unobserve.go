@@ -8,17 +8,13 @@ import (
 func CommandUnobserveHandler(data gjson.Result, observer *Observer, wc chan<- OutgoingMessage) {
 	logger := util.GetLogger()
 
-	if observer.gridObserver == nil {
+	if !observer.unwatch() {
 		logger.Warn("Unobserve command received but client is not observing any grid")
 		wc <- NewOutgoingErrorMessage("not observing any grid")
 		return
 	}
 
-	gm := observer.Manager.GetGame()
-	gr := gm.GetGrid()
-
-	gr.RemoveObserver(observer.gridObserver)
-	observer.gridObserver = nil
+	presence.Broadcast(PresenceUpdate, observer)
 
 	logger.Info("Client stopped observing grid")
 	wc <- OkMessage
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"strings"
+	"time"
+
+	"github.com/henilmalaviya/golw/game"
+	"github.com/henilmalaviya/golw/util"
+	"github.com/tidwall/gjson"
+)
+
+func CommandAddZoneHandler(data gjson.Result, observer *Observer, wc chan<- OutgoingMessage) {
+	logger := util.GetLogger()
+
+	if !observer.IsAdmin() {
+		logger.Warn("Add zone command received from non-admin client")
+		wc <- ErrorAdminRequired
+		return
+	}
+
+	bounds, ok := util.GetBoundsFromData(data, "bounds")
+	if !ok {
+		logger.Warn("Invalid bounds data received in add_zone command")
+		wc <- NewOutgoingErrorMessage("invalid bounds data")
+		return
+	}
+
+	owner := strings.TrimSpace(data.Get("owner").String())
+	if owner == "" {
+		logger.Warn("Add zone command received without owner")
+		wc <- NewOutgoingErrorMessage("owner required")
+		return
+	}
+
+	var allowedUsers []string
+	for _, user := range data.Get("allowed_users").Array() {
+		if name := strings.TrimSpace(user.String()); name != "" {
+			allowedUsers = append(allowedUsers, name)
+		}
+	}
+
+	zone := game.Zone{
+		Bounds:       bounds,
+		Owner:        owner,
+		AllowedUsers: allowedUsers,
+	}
+
+	if expiresIn := data.Get("expires_in"); expiresIn.Exists() {
+		if expiresIn.Int() <= 0 {
+			logger.Warn("Invalid expiry received in add_zone command", "expires_in", expiresIn.Raw)
+			wc <- NewOutgoingErrorMessage("expires_in must be a positive number of seconds")
+			return
+		}
+		expiresAt := time.Now().Add(time.Duration(expiresIn.Int()) * time.Second)
+		zone.ExpiresAt = &expiresAt
+	}
+
+	zone = observer.Manager.AddZone(zone)
+
+	logger.Info("Zone added", "id", zone.ID, "owner", zone.Owner, "bounds", zone.Bounds.ToNestedArray())
+	wc <- NewOutgoingMessage(CodeAddZoneOk, MessageData{"zone": zone})
+}
+
+func CommandRemoveZoneHandler(data gjson.Result, observer *Observer, wc chan<- OutgoingMessage) {
+	logger := util.GetLogger()
+
+	if !observer.IsAdmin() {
+		logger.Warn("Remove zone command received from non-admin client")
+		wc <- ErrorAdminRequired
+		return
+	}
+
+	id := data.Get("id").String()
+	if err := observer.Manager.RemoveZone(id); err != nil {
+		logger.Warn("Failed to remove zone", "id", id, "error", err)
+		wc <- NewOutgoingErrorMessage(err.Error())
+		return
+	}
+
+	logger.Info("Zone removed", "id", id)
+	wc <- OkMessage
+}
+
+func CommandListZonesHandler(data gjson.Result, observer *Observer, wc chan<- OutgoingMessage) {
+	zones := observer.Manager.Zones()
+	wc <- NewOutgoingMessage(CodeListZonesOk, MessageData{"zones": zones})
+}
+
+func init() {
+	registry.Register(CommandAddZone, CommandAddZoneHandler)
+	registry.Register(CommandRemoveZone, CommandRemoveZoneHandler)
+	registry.Register(CommandListZones, CommandListZonesHandler)
+}
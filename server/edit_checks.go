@@ -0,0 +1,40 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/henilmalaviya/gol/grid"
+	"github.com/henilmalaviya/golw/env"
+	"github.com/henilmalaviya/golw/game"
+	"github.com/henilmalaviya/golw/util"
+)
+
+// checkRegionSize applies the observe size limit to an edited region.
+func checkRegionSize(bounds grid.Rectangle) error {
+	maxSize := env.Get().MaxObserveRegionSize
+	if util.DiagonalLength(bounds) > float64(maxSize) {
+		return fmt.Errorf("region diagonal length exceeds maximum allowed (%d)", maxSize)
+	}
+	return nil
+}
+
+// checkEdit validates an edit touching cells, and region when it is not
+// nil, against the world bounds. Protected zones and the population cap are
+// checked by the manager when the edit is applied.
+func checkEdit(observer *Observer, cells []grid.Cell, region *grid.Rectangle) error {
+	return checkEditAs(observer.Manager, cells, region)
+}
+
+// checkEditAs is checkEdit for edits that do not come from a connection.
+func checkEditAs(gm *game.Manager, cells []grid.Cell, region *grid.Rectangle) error {
+	if err := gm.CheckCells(cells); err != nil {
+		return err
+	}
+	if region != nil {
+		if err := gm.CheckCells([]grid.Cell{{X: region.X1, Y: region.Y1}, {X: region.X2, Y: region.Y2}}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
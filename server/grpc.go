@@ -0,0 +1,299 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/henilmalaviya/gol/grid"
+	"github.com/henilmalaviya/golw/env"
+	"github.com/henilmalaviya/golw/game"
+	"github.com/henilmalaviya/golw/rpc"
+	"github.com/henilmalaviya/golw/util"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// observeStreamSize is how many events an Observe call holds for its client.
+// A client that falls further behind has its stream ended.
+const observeStreamSize = 256
+
+// grpcServer implements the gRPC API on top of the game manager, with the
+// same checks as the WebSocket commands.
+type grpcServer struct {
+	rpc.UnimplementedGameServer
+	gm *game.Manager
+}
+
+// NewGRPCServer returns a gRPC server serving the Game service. Edits are
+// only made as a registered user, or with admin access inside protected
+// zones, when they carry its token as a bearer token in their
+// authorization metadata.
+func NewGRPCServer(gm *game.Manager) *grpc.Server {
+	s := grpc.NewServer()
+	rpc.RegisterGameServer(s, &grpcServer{gm: gm})
+	return s
+}
+
+// grpcAuthor returns the author of an edit, checking its name and color as
+// the auth command does. The bearer token in the call's authorization
+// metadata is either the token of the registered user named in the request,
+// which makes the author that user, or the admin token, which makes it an
+// admin. A registered name needs its token, and a wrong token is rejected
+// rather than treated as a regular client.
+func grpcAuthor(ctx context.Context, req *rpc.EditRequest) (game.Author, error) {
+	name := strings.TrimSpace(req.GetName())
+	if len(name) > MaxNameLength {
+		return game.Author{}, status.Errorf(codes.InvalidArgument, "name must be between 1 and %d characters", MaxNameLength)
+	}
+	if req.GetColor() != "" && !colorPattern.MatchString(req.GetColor()) {
+		return game.Author{}, status.Error(codes.InvalidArgument, "color must have the form #rrggbb")
+	}
+	// gRPC calls have no connection to undo from, so the author has no ID
+	// and only a verified user's edits are journaled.
+	author := game.Author{Name: name, Color: req.GetColor()}
+
+	_, registered := userToken(name)
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		if name != "" && registered {
+			return game.Author{}, status.Errorf(codes.Unauthenticated, "%s is a registered user, send its token", name)
+		}
+		return author, nil
+	}
+
+	token, _ := strings.CutPrefix(values[0], "Bearer ")
+	if name != "" && registered && checkUserToken(name, token) == nil {
+		author.User = name
+		return author, nil
+	}
+	if err := checkAdminToken(token); err != nil {
+		return game.Author{}, status.Error(codes.Unauthenticated, err.Error())
+	}
+	author.Admin = true
+	return author, nil
+}
+
+func (s *grpcServer) SetCells(ctx context.Context, req *rpc.EditRequest) (*rpc.EditReply, error) {
+	return s.edit(ctx, req, true)
+}
+
+func (s *grpcServer) ClearCells(ctx context.Context, req *rpc.EditRequest) (*rpc.EditReply, error) {
+	return s.edit(ctx, req, false)
+}
+
+func (s *grpcServer) edit(ctx context.Context, req *rpc.EditRequest, alive bool) (*rpc.EditReply, error) {
+	logger := util.GetLogger()
+
+	cells := fromRPCCells(req.GetCells())
+	if len(cells) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid cells data")
+	}
+
+	author, err := grpcAuthor(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkEditAs(s.gm, cells, nil); err != nil {
+		logger.Warn("Rejected gRPC edit", "alive", alive, "error", err)
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	logger.Info("Editing cells over gRPC", "alive", alive, "count", len(cells))
+	result, err := s.gm.EditCells(cells, alive, author)
+	if err != nil {
+		logger.Warn("Rejected gRPC edit", "alive", alive, "error", err)
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	return &rpc.EditReply{
+		Generation: int64(result.Generation),
+		Changed:    int64(len(result.Changes)),
+	}, nil
+}
+
+func (s *grpcServer) Sync(ctx context.Context, req *rpc.SyncRequest) (*rpc.SyncReply, error) {
+	bounds, ok := fromRPCRectangle(req.GetBounds())
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "bounds not provided")
+	}
+	if maxSize := env.Get().MaxObserveRegionSize; util.DiagonalLength(bounds) > float64(maxSize) {
+		return nil, status.Errorf(codes.InvalidArgument, "bounds diagonal length exceeds maximum allowed (%d)", maxSize)
+	}
+
+	reply := &rpc.SyncReply{
+		Stats:    toRPCStats(s.gm.GetStats()),
+		Topology: toRPCTopology(s.gm.GetTopology()),
+	}
+	for _, cell := range s.gm.GetGame().GetGrid().GetLiveCellCoordinates() {
+		if bounds.PointInside(cell[0], cell[1]) {
+			reply.Cells = append(reply.Cells, toRPCCell(cell[0], cell[1]))
+		}
+	}
+	return reply, nil
+}
+
+func (s *grpcServer) Observe(req *rpc.ObserveRequest, stream grpc.ServerStreamingServer[rpc.ObserveEvent]) error {
+	logger := util.GetLogger()
+
+	bounds, ok := fromRPCRectangle(req.GetBounds())
+	if !ok {
+		return status.Error(codes.InvalidArgument, "invalid bounds data")
+	}
+	if bounds.Width() <= 0 || bounds.Height() <= 0 {
+		return status.Error(codes.InvalidArgument, "bounds must have positive width and height")
+	}
+	if maxSize := env.Get().MaxObserveRegionSize; util.DiagonalLength(bounds) > float64(maxSize) {
+		return status.Errorf(codes.InvalidArgument, "bounds diagonal length exceeds maximum allowed (%d)", maxSize)
+	}
+
+	events := make(chan *rpc.ObserveEvent, observeStreamSize)
+	behind := make(chan struct{})
+	var behindOnce sync.Once
+
+	// Events arrive from the tick loop, which must not wait for a slow
+	// client.
+	observer := game.NewRegionObserver(bounds, func(event grid.ObserverEvent) {
+		e := toRPCEvent(event)
+		if e == nil {
+			return
+		}
+		select {
+		case events <- e:
+		default:
+			behindOnce.Do(func() { close(behind) })
+		}
+	})
+
+	periodWindow := 0
+	if req.GetDetectPeriod() {
+		periodWindow = env.Get().PeriodWindow
+	}
+	s.gm.WatchRegion(observer, bounds, periodWindow)
+	s.gm.AddObserver(observer)
+	defer s.gm.RemoveObserver(observer)
+
+	logger.Info("gRPC observe started", "bounds", bounds.ToNestedArray())
+	defer logger.Info("gRPC observe ended", "bounds", bounds.ToNestedArray())
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-behind:
+			return status.Error(codes.ResourceExhausted, fmt.Sprintf("client fell more than %d events behind", observeStreamSize))
+		case e := <-events:
+			if err := stream.Send(e); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// toRPCEvent converts a region observer's event, returning nil for events
+// that are not streamed.
+func toRPCEvent(event grid.ObserverEvent) *rpc.ObserveEvent {
+	switch e := event.(type) {
+	case game.SetCellEvent:
+		return &rpc.ObserveEvent{Event: &rpc.ObserveEvent_SetCell{SetCell: toRPCCellEdit(e.Cell, e.Author)}}
+	case game.ClearCellEvent:
+		return &rpc.ObserveEvent{Event: &rpc.ObserveEvent_ClearCell{ClearCell: toRPCCellEdit(e.Cell, e.Author)}}
+	case game.TickEvent:
+		if len(e.BornCells) == 0 && len(e.DiedCells) == 0 {
+			return nil
+		}
+		return &rpc.ObserveEvent{Event: &rpc.ObserveEvent_Tick{Tick: &rpc.Tick{
+			Generation: int64(e.Generation),
+			Born:       toRPCCells(e.BornCells),
+			Died:       toRPCCells(e.DiedCells),
+		}}}
+	case game.PeriodEvent:
+		periodic := &rpc.Periodic{
+			Generation:   int64(e.Generation),
+			Period:       int64(e.Period),
+			StabilizedAt: int64(e.StabilizedAt),
+		}
+		if e.Region != nil {
+			periodic.Region = toRPCRectangle(*e.Region)
+		}
+		return &rpc.ObserveEvent{Event: &rpc.ObserveEvent_Periodic{Periodic: periodic}}
+	default:
+		return nil
+	}
+}
+
+func toRPCCell(x, y int) *rpc.Cell {
+	return &rpc.Cell{X: int64(x), Y: int64(y)}
+}
+
+func toRPCCells(cells []grid.Cell) []*rpc.Cell {
+	out := make([]*rpc.Cell, len(cells))
+	for i, c := range cells {
+		out[i] = toRPCCell(c.X, c.Y)
+	}
+	return out
+}
+
+func fromRPCCells(cells []*rpc.Cell) []grid.Cell {
+	out := make([]grid.Cell, 0, len(cells))
+	for _, c := range cells {
+		if c != nil {
+			out = append(out, *grid.NewCellFromCords(int(c.GetX()), int(c.GetY())))
+		}
+	}
+	return out
+}
+
+func toRPCCellEdit(cell grid.Cell, author game.Author) *rpc.CellEdit {
+	edit := &rpc.CellEdit{Cell: toRPCCell(cell.X, cell.Y)}
+	if !author.IsZero() {
+		edit.Author = &rpc.Author{Id: author.ID, Name: author.Name, Color: author.Color}
+	}
+	return edit
+}
+
+func toRPCRectangle(r grid.Rectangle) *rpc.Rectangle {
+	return &rpc.Rectangle{Min: toRPCCell(r.X1, r.Y1), Max: toRPCCell(r.X2, r.Y2)}
+}
+
+func fromRPCRectangle(r *rpc.Rectangle) (grid.Rectangle, bool) {
+	if r.GetMin() == nil || r.GetMax() == nil {
+		return grid.Rectangle{}, false
+	}
+	min, max := r.GetMin(), r.GetMax()
+	return *grid.NewRectangle(int(min.GetX()), int(min.GetY()), int(max.GetX()), int(max.GetY())), true
+}
+
+func toRPCStats(stats game.GameStats) *rpc.Stats {
+	out := &rpc.Stats{
+		Generation:     int64(stats.Generation),
+		BirthCount:     int64(stats.BirthCount),
+		DeathCount:     int64(stats.DeathCount),
+		CulledCount:    int64(stats.CulledCount),
+		Population:     int64(stats.Population),
+		Period:         int64(stats.Period),
+		StabilizedAt:   int64(stats.StabilizedAt),
+		TickRate:       stats.TickRate,
+		TickIntervalMs: stats.TickIntervalMs,
+		LastTickMs:     stats.LastTickMs,
+		TickOverruns:   int64(stats.TickOverruns),
+		SkippedTicks:   int64(stats.SkippedTicks),
+	}
+	if b := stats.Bounds; b != nil {
+		out.Bounds = toRPCRectangle(*grid.NewRectangle(b[0][0], b[0][1], b[1][0], b[1][1]))
+	}
+	return out
+}
+
+func toRPCTopology(topology game.Topology) *rpc.Topology {
+	out := &rpc.Topology{Kind: string(topology.Kind)}
+	if topology.Kind != game.TopologyInfinite {
+		out.Bounds = toRPCRectangle(topology.Bounds)
+	}
+	return out
+}
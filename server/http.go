@@ -0,0 +1,176 @@
+package server
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/henilmalaviya/golw/game"
+	"github.com/henilmalaviya/golw/util"
+	"github.com/tidwall/gjson"
+)
+
+// maxRequestBodySize caps the body of HTTP API requests.
+const maxRequestBodySize = 1 << 20
+
+func writeJSON(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func writeJSONError(w http.ResponseWriter, status int, err string) {
+	writeJSON(w, status, MessageData{"error": err})
+}
+
+// requireAdmin checks the request's bearer token against the admin token.
+func requireAdmin(w http.ResponseWriter, r *http.Request) bool {
+	token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if err := checkAdminToken(token); err != nil {
+		util.GetLogger().Warn("Rejected HTTP API request", "path", r.URL.Path, "error", err)
+		writeJSONError(w, http.StatusUnauthorized, err.Error())
+		return false
+	}
+	return true
+}
+
+// readJSONBody parses a JSON request body.
+func readJSONBody(w http.ResponseWriter, r *http.Request) (gjson.Result, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBodySize))
+	if err != nil || !gjson.ValidBytes(body) {
+		writeJSONError(w, http.StatusBadRequest, "invalid JSON body")
+		return gjson.Result{}, false
+	}
+	return gjson.ParseBytes(body), true
+}
+
+// RandomFillHandler serves POST requests with the same body as the
+// random_fill command. It requires the admin token.
+func RandomFillHandler(gm *game.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := util.GetLogger()
+
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		if !requireAdmin(w, r) {
+			return
+		}
+
+		data, ok := readJSONBody(w, r)
+		if !ok {
+			return
+		}
+
+		fill, err := parseRandomFill(data)
+		if err != nil {
+			logger.Warn("Invalid random fill request", "error", err)
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if err := checkEditAs(gm, nil, &fill.Bounds); err != nil {
+			logger.Warn("Rejected random fill request", "error", err)
+			writeJSONError(w, http.StatusConflict, err.Error())
+			return
+		}
+
+		result, err := gm.RandomFill(fill, game.Author{Admin: true})
+		if err != nil {
+			logger.Warn("Rejected random fill request", "error", err)
+			writeJSONError(w, http.StatusConflict, err.Error())
+			return
+		}
+
+		writeJSON(w, http.StatusOK, MessageData{
+			"generation": result.Generation,
+			"changed":    len(result.Changes),
+			"seed":       fill.Seed,
+		})
+	}
+}
+
+// CensusHandler serves the latest census. A refresh query parameter takes a
+// new one. It requires the admin token.
+func CensusHandler(gm *game.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		if !requireAdmin(w, r) {
+			return
+		}
+
+		refresh := r.URL.Query().Has("refresh")
+		writeJSON(w, http.StatusOK, latestCensus(gm, refresh))
+	}
+}
+
+// StatsHistoryHandler serves the stats history for the from, to and
+// resolution query parameters, as JSON or, with format=csv, as CSV.
+func StatsHistoryHandler(gm *game.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+
+		params := r.URL.Query()
+		query := newHistoryQuery(gm)
+		for key, dst := range map[string]*int{"from": &query.From, "to": &query.To, "resolution": &query.Resolution} {
+			if !params.Has(key) {
+				continue
+			}
+			v, err := strconv.Atoi(params.Get(key))
+			if err != nil {
+				writeJSONError(w, http.StatusBadRequest, "invalid "+key)
+				return
+			}
+			*dst = v
+		}
+
+		samples, err := query.run(gm)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if params.Get("format") != "csv" {
+			writeJSON(w, http.StatusOK, query.reply(samples))
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/csv")
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"generation", "span", "time", "population", "births", "deaths", "x1", "y1", "x2", "y2", "tick_ms"})
+		for _, s := range samples {
+			bounds := []string{"", "", "", ""}
+			if s.Bounds != nil {
+				for i, v := range []int{s.Bounds[0][0], s.Bounds[0][1], s.Bounds[1][0], s.Bounds[1][1]} {
+					bounds[i] = strconv.Itoa(v)
+				}
+			}
+			record := []string{
+				strconv.Itoa(s.Generation),
+				strconv.Itoa(s.Span),
+				s.Time.Format(time.RFC3339Nano),
+				strconv.FormatFloat(s.Population, 'f', -1, 64),
+				strconv.Itoa(s.Births),
+				strconv.Itoa(s.Deaths),
+			}
+			record = append(record, bounds...)
+			record = append(record, strconv.FormatFloat(s.TickMs, 'f', 3, 64))
+			cw.Write(record)
+		}
+		cw.Flush()
+	}
+}
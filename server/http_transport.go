@@ -0,0 +1,314 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/henilmalaviya/golw/game"
+	"github.com/henilmalaviya/golw/util"
+)
+
+const (
+	// sessionQueueSize is how many messages a session holds for its client.
+	// A client that falls further behind is disconnected.
+	sessionQueueSize = 256
+	// eventStreamKeepAlive is how often an idle event stream sends a comment
+	// so proxies don't close it.
+	eventStreamKeepAlive = 15 * time.Second
+	// pollTimeout is how long a poll waits for a message.
+	pollTimeout = 25 * time.Second
+	// pollSessionIdle is how long a polling session lives without a poll.
+	pollSessionIdle = time.Minute
+)
+
+var errSessionBehind = errors.New("session queue full")
+
+// httpSession is the session of a client without a WebSocket. Messages
+// are queued and read by an event stream or by polls, and commands arrive
+// as separate POST requests naming the session.
+type httpSession struct {
+	id         string
+	remoteAddr string
+	observer   *Observer
+
+	queue  chan OutgoingMessage
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// lastSeen is when the session was last polled, in Unix nanoseconds.
+	lastSeen atomic.Int64
+}
+
+func (s *httpSession) Send(msg OutgoingMessage) error {
+	if err := s.ctx.Err(); err != nil {
+		return errSessionClosed
+	}
+
+	select {
+	case s.queue <- msg:
+		return nil
+	default:
+		// A client that stopped reading must not hold on to messages forever.
+		s.Close()
+		return errSessionBehind
+	}
+}
+
+func (s *httpSession) Close() error {
+	s.cancel()
+	return nil
+}
+
+func (s *httpSession) RemoteAddr() string {
+	return s.remoteAddr
+}
+
+func (s *httpSession) Context() context.Context {
+	return s.ctx
+}
+
+func (s *httpSession) touch() {
+	s.lastSeen.Store(time.Now().UnixNano())
+}
+
+func (s *httpSession) idle() time.Duration {
+	return time.Since(time.Unix(0, s.lastSeen.Load()))
+}
+
+// httpSessions tracks the open sessions by id.
+type httpSessions struct {
+	mutex    sync.Mutex
+	sessions map[string]*httpSession
+}
+
+var sessions = &httpSessions{sessions: make(map[string]*httpSession)}
+
+// open creates a session for the client of the request and joins its
+// observer.
+func (h *httpSessions) open(r *http.Request, gm *game.Manager) *httpSession {
+	id := make([]byte, 16)
+	rand.Read(id)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	session := &httpSession{
+		id:         hex.EncodeToString(id),
+		remoteAddr: r.RemoteAddr,
+		queue:      make(chan OutgoingMessage, sessionQueueSize),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+	session.touch()
+	session.observer = joinObserver(session, gm)
+
+	h.mutex.Lock()
+	h.sessions[session.id] = session
+	h.mutex.Unlock()
+	return session
+}
+
+// get returns the open session with the id in the request's session
+// parameter.
+func (h *httpSessions) get(r *http.Request) *httpSession {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.sessions[r.URL.Query().Get("session")]
+}
+
+// end removes a session and releases its observer. It is safe to call more
+// than once.
+func (h *httpSessions) end(session *httpSession) {
+	h.mutex.Lock()
+	_, ok := h.sessions[session.id]
+	delete(h.sessions, session.id)
+	h.mutex.Unlock()
+
+	if ok {
+		leaveObserver(session.observer)
+	}
+}
+
+// reapWhenIdle ends a polling session once it has not been polled for
+// pollSessionIdle.
+func (h *httpSessions) reapWhenIdle(session *httpSession) {
+	ticker := time.NewTicker(pollSessionIdle / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-session.ctx.Done():
+			h.end(session)
+			return
+		case <-ticker.C:
+			if session.idle() > pollSessionIdle {
+				util.GetLogger().Info("Polling session expired", "session", session.id, "client", session.remoteAddr)
+				h.end(session)
+				return
+			}
+		}
+	}
+}
+
+// EventStreamHandler serves the protocol's messages as server-sent events,
+// for clients that can't open a WebSocket. Each event's data is a
+// "code;{json}" frame, the first one being a session message with the id
+// that commands are posted with.
+func EventStreamHandler(gm *game.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := util.GetLogger()
+
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			writeJSONError(w, http.StatusInternalServerError, "streaming not supported")
+			return
+		}
+
+		session := sessions.open(r, gm)
+		defer sessions.end(session)
+		logger.Info("Event stream opened", "session", session.id, "client", session.remoteAddr)
+		defer logger.Info("Event stream closed", "session", session.id, "client", session.remoteAddr)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+
+		writeEvent(w, OutgoingMessage{Code: CodeSession, Data: MessageData{"session": session.id}})
+		flusher.Flush()
+
+		keepAlive := time.NewTicker(eventStreamKeepAlive)
+		defer keepAlive.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-session.ctx.Done():
+				return
+			case msg := <-session.queue:
+				if err := writeEvent(w, msg); err != nil {
+					return
+				}
+			case <-keepAlive.C:
+				if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+					return
+				}
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func writeEvent(w io.Writer, msg OutgoingMessage) error {
+	_, err := io.WriteString(w, "data: "+strings.TrimSuffix(msg.String(), "\r\n")+"\n\n")
+	return err
+}
+
+// PollHandler serves the protocol over plain HTTP requests. POST opens a
+// session and returns its id; GET with the session parameter waits for
+// messages and returns them as "code;{json}\r\n" frames, the same as over
+// a WebSocket.
+func PollHandler(gm *game.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			session := sessions.open(r, gm)
+			go sessions.reapWhenIdle(session)
+			util.GetLogger().Info("Polling session opened", "session", session.id, "client", session.remoteAddr)
+			writeJSON(w, http.StatusOK, MessageData{"session": session.id})
+		case http.MethodGet:
+			poll(w, r)
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	}
+}
+
+func poll(w http.ResponseWriter, r *http.Request) {
+	session := sessions.get(r)
+	if session == nil {
+		writeJSONError(w, http.StatusNotFound, "unknown session")
+		return
+	}
+	session.touch()
+	defer session.touch()
+
+	var frames strings.Builder
+	timeout := time.NewTimer(pollTimeout)
+	defer timeout.Stop()
+
+	select {
+	case msg := <-session.queue:
+		frames.WriteString(msg.String())
+	case <-session.ctx.Done():
+		writeJSONError(w, http.StatusGone, errSessionClosed.Error())
+		return
+	case <-timeout.C:
+	case <-r.Context().Done():
+		return
+	}
+
+	// Return whatever else is waiting along with the first message.
+	for drained := false; !drained; {
+		select {
+		case msg := <-session.queue:
+			frames.WriteString(msg.String())
+		default:
+			drained = true
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	if frames.Len() == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	io.WriteString(w, frames.String())
+}
+
+// PostCommandHandler takes a command for the session named by the session
+// parameter. The body is the same JSON as a WebSocket message; the reply is
+// delivered on the session's event stream or poll, carrying the command's
+// id if it has one.
+func PostCommandHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	session := sessions.get(r)
+	if session == nil {
+		writeJSONError(w, http.StatusNotFound, "unknown session")
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBodySize))
+	var msg IncomingMessage
+	if err == nil {
+		err = json.Unmarshal(body, &msg)
+	}
+	if err != nil {
+		util.GetLogger().Warn("Invalid message received", "session", session.id, "client", session.remoteAddr, "error", err.Error())
+		writeJSONError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	go session.observer.HandleIncomingMessage(msg)
+	w.WriteHeader(http.StatusAccepted)
+}
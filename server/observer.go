@@ -1,52 +1,254 @@
 package server
 
 import (
-	"compress/flate"
+	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 
-	"github.com/gorilla/websocket"
 	"github.com/henilmalaviya/gol/grid"
+	"github.com/henilmalaviya/golw/env"
 	"github.com/henilmalaviya/golw/game"
 	"github.com/henilmalaviya/golw/util"
 )
 
+// observerColors is the palette new connections are assigned from.
+var observerColors = []string{
+	"#e6194b", "#3cb44b", "#4363d8", "#f58231", "#911eb4", "#42d4f4",
+	"#f032e6", "#bfef45", "#469990", "#9a6324", "#800000", "#000075",
+}
+
+var observerSeq atomic.Int64
+
+// Observer is a client of the game. It is a Session itself, so messages are
+// sent and the client is identified the same way whatever it connected with.
 type Observer struct {
-	Conn    *websocket.Conn
+	Session
 	Manager *game.Manager
 
-	gridObserver *grid.RegionObserver
+	// ID identifies the connection for attribution and presence.
+	ID  string
+	seq int64
+
+	gridObserver  *game.RegionObserver
+	watchDone     chan struct{}
+	alertObserver *grid.GlobalObserver
+
+	admin bool
+
+	name               string
+	user               string
+	color              string
+	presenceSubscribed bool
+	statsStop          chan struct{}
+	stateMutex         sync.RWMutex
+
+	cursorLimiter *util.RateLimiter
+	chatLimiter   *util.RateLimiter
+}
+
+// Author returns the identity attached to the observer's edits.
+func (o *Observer) Author() game.Author {
+	o.stateMutex.RLock()
+	defer o.stateMutex.RUnlock()
+	return game.Author{ID: o.ID, Name: o.name, Color: o.color, User: o.user, Admin: o.admin}
+}
+
+func (o *Observer) SetName(name string) {
+	o.stateMutex.Lock()
+	o.name = name
+	o.stateMutex.Unlock()
+}
+
+// SetUser sets the registered user the observer proved to be, or clears it
+// when user is empty.
+func (o *Observer) SetUser(user string) {
+	o.stateMutex.Lock()
+	o.user = user
+	o.stateMutex.Unlock()
+}
+
+func (o *Observer) SetColor(color string) {
+	o.stateMutex.Lock()
+	o.color = color
+	o.stateMutex.Unlock()
+}
+
+func (o *Observer) IsPresenceSubscribed() bool {
+	o.stateMutex.RLock()
+	defer o.stateMutex.RUnlock()
+	return o.presenceSubscribed
+}
+
+func (o *Observer) SetPresenceSubscribed(subscribed bool) {
+	o.stateMutex.Lock()
+	o.presenceSubscribed = subscribed
+	o.stateMutex.Unlock()
+}
+
+// IsAdmin reports whether the observer authenticated with the admin token.
+func (o *Observer) IsAdmin() bool {
+	o.stateMutex.RLock()
+	defer o.stateMutex.RUnlock()
+	return o.admin
+}
+
+// IsObserving reports whether (x, y) is inside the observer's region.
+func (o *Observer) IsObserving(x, y int) bool {
+	o.stateMutex.RLock()
+	gridObserver := o.gridObserver
+	o.stateMutex.RUnlock()
 
-	connWriteMutex sync.Mutex
+	if gridObserver == nil {
+		return false
+	}
+	region := gridObserver.GetRegion()
+	return region.PointInside(x, y)
 }
 
-func (o *Observer) Close() {
-	o.Conn.Close()
-	gr := o.Manager.GetGame().GetGrid()
+// watch observes bounds, creating the region observer with updateFunc the
+// first time. It returns a channel closed once that region observer is
+// removed and updateFunc is no longer called, or nil if the existing one was
+// moved. It reports false, without observing, once the observer is closed.
+func (o *Observer) watch(bounds grid.Rectangle, periodWindow int, updateFunc func(event grid.ObserverEvent)) (<-chan struct{}, bool) {
+	o.stateMutex.Lock()
+	defer o.stateMutex.Unlock()
+
+	// Close ends the session before it removes the region observer, so one
+	// added after that would never be removed.
+	if o.Context().Err() != nil {
+		return nil, false
+	}
 
 	if o.gridObserver != nil {
-		gr.RemoveObserver(o.gridObserver)
+		o.Manager.WatchRegion(o.gridObserver, bounds, periodWindow)
+		return nil, true
+	}
+
+	o.gridObserver = game.NewRegionObserver(bounds, updateFunc)
+	o.watchDone = make(chan struct{})
+	o.Manager.WatchRegion(o.gridObserver, bounds, periodWindow)
+	o.Manager.AddObserver(o.gridObserver)
+	return o.watchDone, true
+}
+
+// unwatch stops observing. It reports false if no region was observed.
+func (o *Observer) unwatch() bool {
+	o.stateMutex.Lock()
+	gridObserver, done := o.gridObserver, o.watchDone
+	o.gridObserver, o.watchDone = nil, nil
+	o.stateMutex.Unlock()
+
+	if gridObserver == nil {
+		return false
+	}
+	// RemoveObserver waits for events being delivered, so none follow.
+	o.Manager.RemoveObserver(gridObserver)
+	close(done)
+	return true
+}
+
+func (o *Observer) Presence() PresenceInfo {
+	o.stateMutex.RLock()
+	info := PresenceInfo{
+		ID:    o.ID,
+		Name:  o.name,
+		Color: o.color,
+		Admin: o.admin,
+	}
+	gridObserver := o.gridObserver
+	o.stateMutex.RUnlock()
+
+	if gridObserver != nil {
+		region := gridObserver.GetRegion()
+		bounds := region.ToNestedArray()
+		info.Bounds = &bounds
+	}
+	return info
+}
+
+// SubscribeStats replaces any previous stats subscription with one every
+// interval, and returns a channel closed when it is replaced or the observer
+// closes. An interval of zero unsubscribes and returns nil.
+func (o *Observer) SubscribeStats(interval time.Duration) <-chan struct{} {
+	o.stateMutex.Lock()
+	defer o.stateMutex.Unlock()
+
+	if o.statsStop != nil {
+		close(o.statsStop)
+		o.statsStop = nil
+	}
+	if interval <= 0 || o.Context().Err() != nil {
+		return nil
+	}
+
+	o.statsStop = make(chan struct{})
+	return o.statsStop
+}
+
+// Close ends the session and removes what the observer added to the
+// manager.
+func (o *Observer) Close() error {
+	// The session ends first, so commands still running can't add more.
+	err := o.Session.Close()
+
+	o.SubscribeStats(0)
+	o.unwatch()
+
+	o.stateMutex.Lock()
+	alertObserver := o.alertObserver
+	o.alertObserver = nil
+	o.stateMutex.Unlock()
+
+	if alertObserver != nil {
+		o.Manager.RemoveObserver(alertObserver)
 	}
 
-	o.gridObserver = nil
+	return err
 }
 
-func NewObserver(conn *websocket.Conn, gm *game.Manager) *Observer {
-	conn.EnableWriteCompression(true)
-	conn.SetCompressionLevel(flate.BestSpeed)
+func NewObserver(session Session, gm *game.Manager) *Observer {
+	seq := observerSeq.Add(1)
 	return &Observer{
-		Conn:    conn,
+		Session: session,
 		Manager: gm,
+		ID:      fmt.Sprintf("c%d", seq),
+		seq:     seq,
+		color:   observerColors[int(seq-1)%len(observerColors)],
+
+		cursorLimiter: util.NewRateLimiter(cursorRate()),
+		chatLimiter:   util.NewRateLimiter(chatRate()),
+	}
+}
+
+// cursorRate returns the rate and burst of cursor updates per second.
+func cursorRate() (float64, int) {
+	limit := env.Get().CursorRateLimit
+	return float64(limit), max(limit, 1)
+}
+
+// chatRate returns the rate per second and burst of chat messages, which
+// are configured per minute.
+func chatRate() (float64, int) {
+	limit := env.Get().ChatRateLimit
+	return float64(limit) / 60, max(limit/4, 1)
+}
+
+// ApplyRateLimits updates the rate limits of every connection from the
+// current settings.
+func ApplyRateLimits() {
+	for _, o := range presence.Observers() {
+		o.cursorLimiter.SetRate(cursorRate())
+		o.chatLimiter.SetRate(chatRate())
 	}
 }
 
 func (o *Observer) HandleIncomingMessage(msg IncomingMessage) {
 	logger := util.GetLogger()
 	logger.Debug("Processing incoming message", "command", string(msg.Command))
-	registry.Handle(msg.Command, msg.Data, o)
+	registry.Handle(msg, o)
 }
 
 func (o *Observer) SendOutgoingMessage(msg OutgoingMessage) error {
-	o.connWriteMutex.Lock()
-	defer o.connWriteMutex.Unlock()
-	return o.Conn.WriteMessage(websocket.TextMessage, []byte(msg.String()))
+	return o.Send(msg)
 }
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"sort"
+	"sync"
+)
+
+// PresenceInfo describes a connected user as shown to other clients.
+type PresenceInfo struct {
+	ID     string     `json:"id"`
+	Name   string     `json:"name,omitempty"`
+	Color  string     `json:"color"`
+	Admin  bool       `json:"admin,omitempty"`
+	Bounds *[2][2]int `json:"bounds,omitempty"`
+}
+
+const (
+	PresenceJoin   = "join"
+	PresenceLeave  = "leave"
+	PresenceUpdate = "update"
+)
+
+// PresenceHub keeps track of connected observers and pushes join, leave and
+// update events to the ones that subscribed with the presence command.
+type PresenceHub struct {
+	observers map[*Observer]struct{}
+	mutex     sync.RWMutex
+}
+
+func NewPresenceHub() *PresenceHub {
+	return &PresenceHub{
+		observers: make(map[*Observer]struct{}),
+	}
+}
+
+func (h *PresenceHub) Join(observer *Observer) {
+	h.mutex.Lock()
+	h.observers[observer] = struct{}{}
+	h.mutex.Unlock()
+
+	h.Broadcast(PresenceJoin, observer)
+}
+
+func (h *PresenceHub) Leave(observer *Observer) {
+	h.mutex.Lock()
+	delete(h.observers, observer)
+	h.mutex.Unlock()
+
+	h.Broadcast(PresenceLeave, observer)
+}
+
+func (h *PresenceHub) Count() int {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	return len(h.observers)
+}
+
+// Observers returns the connected observers ordered by id.
+func (h *PresenceHub) Observers() []*Observer {
+	h.mutex.RLock()
+	observers := make([]*Observer, 0, len(h.observers))
+	for o := range h.observers {
+		observers = append(observers, o)
+	}
+	h.mutex.RUnlock()
+
+	sort.Slice(observers, func(i, j int) bool {
+		return observers[i].seq < observers[j].seq
+	})
+	return observers
+}
+
+func (h *PresenceHub) List() []PresenceInfo {
+	observers := h.Observers()
+	list := make([]PresenceInfo, len(observers))
+	for i, o := range observers {
+		list[i] = o.Presence()
+	}
+	return list
+}
+
+// Broadcast sends a presence event about observer to every subscriber other
+// than observer itself.
+func (h *PresenceHub) Broadcast(event string, observer *Observer) {
+	msg := NewOutgoingMessage(CodePresenceEvent, MessageData{
+		"event": event,
+		"user":  observer.Presence(),
+	})
+
+	for _, o := range h.Observers() {
+		if o == observer || !o.IsPresenceSubscribed() {
+			continue
+		}
+		o.SendOutgoingMessage(msg)
+	}
+}
@@ -23,20 +23,26 @@ func (r *CommandRegistry) Register(command Command, handler CommandHandler) {
 	r.handlers[command] = handler
 }
 
-func (r *CommandRegistry) Handle(command Command, data MessageData, observer *Observer) {
+// Handle runs the handler of a message's command and sends what it replies.
+// The first message that is not an event is the reply, and carries the
+// message's id. It returns once the handler has returned and what it sent
+// was forwarded. Handlers of streaming commands, such as observe and
+// subscribe_stats, keep running until their stream ends.
+func (r *CommandRegistry) Handle(msg IncomingMessage, observer *Observer) {
 	logger := util.GetLogger()
+	command := msg.Command
 	handler, exists := r.handlers[command]
 	if !exists {
 		logger.Warn("Unknown command received", "command", string(command))
-		observer.SendOutgoingMessage(ErrorUnknownCommand)
+		observer.SendOutgoingMessage(ErrorUnknownCommand.WithID(msg.ID))
 		return
 	}
 
-	marshalData, err := json.Marshal(data)
+	marshalData, err := json.Marshal(msg.Data)
 
 	if err != nil {
 		logger.Error("Failed to marshal command data", "command", string(command), "error", err.Error())
-		observer.SendOutgoingMessage(ErrorUnknownCommand)
+		observer.SendOutgoingMessage(ErrorUnknownCommand.WithID(msg.ID))
 		return
 	}
 
@@ -45,12 +51,24 @@ func (r *CommandRegistry) Handle(command Command, data MessageData, observer *Ob
 	logger.Debug("Executing command", "command", string(command))
 
 	ch := NewOutgoingMessageChannel()
-	go handler(parsedData, observer, ch)
-	for msg := range ch {
-		if err := observer.SendOutgoingMessage(msg); err != nil {
+	go func() {
+		defer close(ch)
+		handler(parsedData, observer, ch)
+	}()
+
+	// Once sending fails the rest is drained, so the handler never blocks.
+	replied, failed := false, false
+	for out := range ch {
+		if failed {
+			continue
+		}
+		if !replied && !out.Code.IsEvent() {
+			out = out.WithID(msg.ID)
+			replied = true
+		}
+		if err := observer.SendOutgoingMessage(out); err != nil {
 			logger.Error("Failed to send message to client", "error", err.Error())
-			return
+			failed = true
 		}
 	}
-	close(ch)
 }
@@ -0,0 +1,278 @@
+package server
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/henilmalaviya/gol/grid"
+	"github.com/henilmalaviya/golw/game"
+	"github.com/tidwall/gjson"
+)
+
+// newTestObserver joins an observer on a memory session. It leaves when the
+// test ends, which also stops the goroutines handling its commands.
+func newTestObserver(t *testing.T, gm *game.Manager) (*Observer, *MemorySession) {
+	session := NewMemorySession("test")
+	observer := joinObserver(session, gm)
+	t.Cleanup(func() { leaveObserver(observer) })
+	return observer, session
+}
+
+// handle runs a command for the observer in the background, as the
+// transports do. Streaming commands keep running until their stream ends.
+func handle(observer *Observer, id string, command Command, data MessageData) {
+	go observer.HandleIncomingMessage(IncomingMessage{ID: id, Command: command, Data: data})
+}
+
+// next returns the next message sent to the session.
+func next(t *testing.T, session *MemorySession) OutgoingMessage {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	msg, err := session.Next(ctx)
+	if err != nil {
+		t.Fatalf("no message received: %v", err)
+	}
+	return msg
+}
+
+func TestHandleUnknownCommand(t *testing.T) {
+	observer, session := newTestObserver(t, game.NewManager())
+
+	handle(observer, "1", "no_such_command", nil)
+
+	msg := next(t, session)
+	if msg.Code != CodeError || msg.Data["error"] != "unknown command" {
+		t.Fatalf("got %s, want the unknown command error", msg)
+	}
+	if msg.Data["request_id"] != "1" {
+		t.Errorf("reply id is %v, want 1", msg.Data["request_id"])
+	}
+}
+
+func TestHandleAuthKeepsConnectionID(t *testing.T) {
+	observer, session := newTestObserver(t, game.NewManager())
+
+	handle(observer, "a", CommandAuth, MessageData{"name": "tester"})
+
+	msg := next(t, session)
+	if msg.Code != CodeAuthOk {
+		t.Fatalf("got %s, want auth_ok", msg)
+	}
+	if msg.Data["id"] != observer.ID {
+		t.Errorf("connection id is %v, want %s", msg.Data["id"], observer.ID)
+	}
+	if msg.Data["request_id"] != "a" {
+		t.Errorf("reply id is %v, want a", msg.Data["request_id"])
+	}
+}
+
+func TestHandleSetCells(t *testing.T) {
+	gm := game.NewManager()
+	observer, session := newTestObserver(t, gm)
+
+	handle(observer, "7", CommandSetCells, MessageData{"cells": [][]int{{1, 2}, {3, 4}}})
+
+	msg := next(t, session)
+	if msg.Code != CodeOk || msg.Data["request_id"] != "7" {
+		t.Fatalf("got %s, want ok with id 7", msg)
+	}
+	if msg.Data["changed"] != 2 {
+		t.Errorf("changed is %v, want 2", msg.Data["changed"])
+	}
+
+	gr := gm.GetGame().GetGrid()
+	if !gr.IsAlive(1, 2) || !gr.IsAlive(3, 4) {
+		t.Error("cells were not set")
+	}
+}
+
+func TestHandleEditInProtectedZone(t *testing.T) {
+	gm := game.NewManager()
+	gm.AddZone(game.Zone{Bounds: *grid.NewRectangle(0, 0, 9, 9), Owner: "someone"})
+	observer, session := newTestObserver(t, gm)
+
+	handle(observer, "", CommandSetCells, MessageData{"cells": [][]int{{5, 5}}})
+
+	if msg := next(t, session); msg.Code != CodeError {
+		t.Fatalf("got %s, want an error", msg)
+	}
+	if gm.GetGame().GetGrid().IsAlive(5, 5) {
+		t.Error("cell in the protected zone was set")
+	}
+}
+
+func TestHandleObserve(t *testing.T) {
+	gm := game.NewManager()
+	observer, session := newTestObserver(t, gm)
+
+	handle(observer, "o", CommandObserve, MessageData{"bounds": [][]int{{0, 0}, {10, 10}}})
+
+	msg := next(t, session)
+	if msg.Code != CodeObserveOk || msg.Data["request_id"] != "o" {
+		t.Fatalf("got %s, want observe_ok with id o", msg)
+	}
+
+	author := game.Author{ID: "c0", Name: "someone"}
+	gm.EditCells([]grid.Cell{{X: 5, Y: 5}, {X: 50, Y: 50}}, true, author)
+
+	msg = next(t, session)
+	if msg.Code != CodeObserveEvent || msg.Data["event"] != grid.SetCellEventType {
+		t.Fatalf("got %s, want a set cell event", msg)
+	}
+	if want := editEventData(grid.Cell{X: 5, Y: 5}, author); !reflect.DeepEqual(msg.Data["data"], want) {
+		t.Errorf("event data is %v, want %v", msg.Data["data"], want)
+	}
+
+	// The lone cell dies; the one outside the region is not reported.
+	gm.Tick()
+
+	msg = next(t, session)
+	if msg.Code != CodeObserveEvent || msg.Data["event"] != grid.TickEventType {
+		t.Fatalf("got %s, want a tick event", msg)
+	}
+	want := map[string][][]int{"bornCells": {}, "diedCells": {{5, 5}}}
+	if !reflect.DeepEqual(msg.Data["data"], want) {
+		t.Errorf("tick data is %v, want %v", msg.Data["data"], want)
+	}
+}
+
+func TestObserveAfterSessionCloses(t *testing.T) {
+	gm := game.NewManager()
+	observer, session := newTestObserver(t, gm)
+
+	handle(observer, "", CommandObserve, MessageData{"bounds": [][]int{{0, 0}, {50, 50}}})
+	if msg := next(t, session); msg.Code != CodeObserveOk {
+		t.Fatalf("got %s, want observe_ok", msg)
+	}
+	session.Close()
+
+	// Nothing reads the events any more, so they must not hold up edits or
+	// leaving.
+	done := make(chan struct{})
+	go func() {
+		for i := range 500 {
+			gm.EditCells([]grid.Cell{{X: i % 50, Y: i / 50}}, true, game.Author{})
+		}
+		leaveObserver(observer)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("edits blocked on a closed session")
+	}
+}
+
+func TestConcurrentObserveAndCursor(t *testing.T) {
+	gm := game.NewManager()
+	watcher, _ := newTestObserver(t, gm)
+	mover, _ := newTestObserver(t, gm)
+
+	// Moving the region races with the cursor fan-out reading it, which the
+	// race detector catches.
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			watcher.watch(*grid.NewRectangle(0, 0, 10+i, 10+i), 0, func(grid.ObserverEvent) {})
+		}()
+		go func() {
+			defer wg.Done()
+			CommandCursorHandler(gjson.Parse(`{"x":5,"y":5}`), mover, NewOutgoingMessageChannel())
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSubscribeStatsAckFirst(t *testing.T) {
+	observer, session := newTestObserver(t, game.NewManager())
+
+	handle(observer, "s", CommandSubscribeStats, MessageData{"interval": MinStatsInterval.Milliseconds()})
+
+	if msg := next(t, session); msg.Code != CodeSubscribeStatsOk {
+		t.Fatalf("got %s, want subscribe_stats_ok first", msg)
+	}
+	for range 2 {
+		if msg := next(t, session); msg.Code != CodeStats {
+			t.Fatalf("got %s, want stats", msg)
+		}
+	}
+
+	// Unsubscribing stops the pushes.
+	handle(observer, "", CommandSubscribeStats, MessageData{"interval": 0})
+	for {
+		msg := next(t, session)
+		if msg.Code == CodeSubscribeStatsOk {
+			break
+		}
+		if msg.Code != CodeStats {
+			t.Fatalf("got %s, want stats or subscribe_stats_ok", msg)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*MinStatsInterval)
+	defer cancel()
+	if msg, err := session.Next(ctx); err == nil {
+		t.Errorf("got %s after unsubscribing", msg)
+	}
+}
+
+// handleSync runs a command for the observer and reports whether Handle
+// returned within a second.
+func handleSync(observer *Observer, id string, command Command, data MessageData) bool {
+	done := make(chan struct{})
+	go func() {
+		observer.HandleIncomingMessage(IncomingMessage{ID: id, Command: command, Data: data})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(time.Second):
+		return false
+	}
+}
+
+func TestHandleReturns(t *testing.T) {
+	observer, session := newTestObserver(t, game.NewManager())
+
+	if !handleSync(observer, "1", CommandSetCells, MessageData{"cells": [][]int{{1, 2}}}) {
+		t.Fatal("set_cells did not return")
+	}
+	if msg := next(t, session); msg.Code != CodeOk {
+		t.Fatalf("got %s, want ok", msg)
+	}
+
+	// Observing streams until the client stops.
+	observing := make(chan bool, 1)
+	go func() {
+		observing <- handleSync(observer, "2", CommandObserve, MessageData{"bounds": [][]int{{0, 0}, {10, 10}}})
+	}()
+	if msg := next(t, session); msg.Code != CodeObserveOk {
+		t.Fatalf("got %s, want observe_ok", msg)
+	}
+
+	// Moving the region is a command of its own.
+	if !handleSync(observer, "3", CommandObserve, MessageData{"bounds": [][]int{{5, 5}, {20, 20}}}) {
+		t.Fatal("observing again did not return")
+	}
+	if msg := next(t, session); msg.Code != CodeObserveOk {
+		t.Fatalf("got %s, want observe_ok", msg)
+	}
+
+	if !handleSync(observer, "4", CommandUnobserve, nil) {
+		t.Fatal("unobserve did not return")
+	}
+	if !<-observing {
+		t.Error("observe kept running after unobserve")
+	}
+}
@@ -18,6 +18,22 @@ var upgrader = websocket.Upgrader{
 
 var registry = NewCommandRegistry()
 
+var presence = NewPresenceHub()
+
+// joinObserver creates the observer of a new connection and announces it.
+func joinObserver(session Session, gm *game.Manager) *Observer {
+	observer := NewObserver(session, gm)
+	presence.Join(observer)
+	return observer
+}
+
+// leaveObserver releases what a connection held once it is gone.
+func leaveObserver(observer *Observer) {
+	presence.Leave(observer)
+	observer.Manager.DropJournal(observer.Author())
+	observer.Close()
+}
+
 func HandleConnection(conn *websocket.Conn, gm *game.Manager) {
 	defer conn.Close()
 
@@ -25,9 +41,9 @@ func HandleConnection(conn *websocket.Conn, gm *game.Manager) {
 	clientAddr := conn.RemoteAddr().String()
 	logger.Info("WebSocket connection established", "client", clientAddr)
 
-	observer := NewObserver(conn, gm)
+	observer := joinObserver(newWebSocketSession(conn), gm)
 	defer func() {
-		observer.Close()
+		leaveObserver(observer)
 		logger.Info("WebSocket connection closed", "client", clientAddr)
 	}()
 
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"compress/flate"
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+var errSessionClosed = errors.New("session closed")
+
+// Session is a client's connection, so commands and events work the same
+// whatever the connection is made of.
+type Session interface {
+	// Send delivers a message to the client. It is safe for concurrent use.
+	Send(msg OutgoingMessage) error
+	// Close ends the session.
+	Close() error
+	// RemoteAddr identifies the client in logs.
+	RemoteAddr() string
+	// Context is done once the session has ended.
+	Context() context.Context
+}
+
+// webSocketSession sends messages as WebSocket text messages.
+type webSocketSession struct {
+	conn       *websocket.Conn
+	writeMutex sync.Mutex
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newWebSocketSession(conn *websocket.Conn) *webSocketSession {
+	conn.EnableWriteCompression(true)
+	conn.SetCompressionLevel(flate.BestSpeed)
+	ctx, cancel := context.WithCancel(context.Background())
+	return &webSocketSession{conn: conn, ctx: ctx, cancel: cancel}
+}
+
+func (s *webSocketSession) Send(msg OutgoingMessage) error {
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+	return s.conn.WriteMessage(websocket.TextMessage, []byte(msg.String()))
+}
+
+func (s *webSocketSession) Close() error {
+	s.cancel()
+	return s.conn.Close()
+}
+
+func (s *webSocketSession) RemoteAddr() string {
+	return s.conn.RemoteAddr().String()
+}
+
+func (s *webSocketSession) Context() context.Context {
+	return s.ctx
+}
+
+// MemorySession is a session that keeps what is sent to it, for driving an
+// observer without a network connection, such as in tests or from another
+// transport.
+type MemorySession struct {
+	remoteAddr string
+
+	mutex    sync.Mutex
+	messages []OutgoingMessage
+	read     int
+	sent     chan struct{}
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewMemorySession returns an open session identified by remoteAddr.
+func NewMemorySession(remoteAddr string) *MemorySession {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &MemorySession{
+		remoteAddr: remoteAddr,
+		sent:       make(chan struct{}),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+func (s *MemorySession) Send(msg OutgoingMessage) error {
+	if err := s.ctx.Err(); err != nil {
+		return errSessionClosed
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.messages = append(s.messages, msg)
+	close(s.sent)
+	s.sent = make(chan struct{})
+	return nil
+}
+
+func (s *MemorySession) Close() error {
+	s.cancel()
+	return nil
+}
+
+func (s *MemorySession) RemoteAddr() string {
+	return s.remoteAddr
+}
+
+func (s *MemorySession) Context() context.Context {
+	return s.ctx
+}
+
+// Messages returns every message sent to the session so far.
+func (s *MemorySession) Messages() []OutgoingMessage {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]OutgoingMessage(nil), s.messages...)
+}
+
+// Next returns the oldest message not yet returned by Next, waiting for one
+// to be sent until ctx is done.
+func (s *MemorySession) Next(ctx context.Context) (OutgoingMessage, error) {
+	for {
+		s.mutex.Lock()
+		if s.read < len(s.messages) {
+			msg := s.messages[s.read]
+			s.read++
+			s.mutex.Unlock()
+			return msg, nil
+		}
+		sent := s.sent
+		s.mutex.Unlock()
+
+		select {
+		case <-sent:
+		case <-ctx.Done():
+			return OutgoingMessage{}, ctx.Err()
+		}
+	}
+}
@@ -3,6 +3,8 @@ package server
 import (
 	"encoding/json"
 	"fmt"
+
+	"github.com/henilmalaviya/golw/game"
 )
 
 type Command string
@@ -12,26 +14,81 @@ type Code string
 type MessageData map[string]interface{}
 
 const (
-	CommandSetCells   Command = "set_cells"
-	CommandClearCells Command = "clear_cells"
-	CommandSync       Command = "sync"
-	CommandObserve    Command = "observe"
-	CommandUnobserve  Command = "unobserve"
+	CommandSetCells            Command = "set_cells"
+	CommandClearCells          Command = "clear_cells"
+	CommandSync                Command = "sync"
+	CommandObserve             Command = "observe"
+	CommandUnobserve           Command = "unobserve"
+	CommandAuth                Command = "auth"
+	CommandPause               Command = "pause"
+	CommandResume              Command = "resume"
+	CommandPresence            Command = "presence"
+	CommandCursor              Command = "cursor"
+	CommandChat                Command = "chat"
+	CommandUndo                Command = "undo"
+	CommandRedo                Command = "redo"
+	CommandAddZone             Command = "add_zone"
+	CommandRemoveZone          Command = "remove_zone"
+	CommandListZones           Command = "list_zones"
+	CommandToggleCells         Command = "toggle_cells"
+	CommandReplaceRegion       Command = "replace_region"
+	CommandStamp               Command = "stamp"
+	CommandScheduleEdit        Command = "schedule_edit"
+	CommandListScheduledEdits  Command = "list_scheduled_edits"
+	CommandCancelScheduledEdit Command = "cancel_scheduled_edit"
+	CommandRandomFill          Command = "random_fill"
+	CommandCensus              Command = "census"
+	CommandStatsHistory        Command = "stats_history"
+	CommandLocate              Command = "locate"
+	CommandSubscribeStats      Command = "subscribe_stats"
 )
 
 const (
-	CodeOk           Code = "ok"
-	CodeObserveOk    Code = "observe_ok"
-	CodeSyncOk       Code = "sync_ok"
-	CodeError        Code = "error"
-	CodeObserveEvent Code = "observe_event"
+	CodeOk                   Code = "ok"
+	CodeObserveOk            Code = "observe_ok"
+	CodeSyncOk               Code = "sync_ok"
+	CodeError                Code = "error"
+	CodeObserveEvent         Code = "observe_event"
+	CodeAuthOk               Code = "auth_ok"
+	CodeAlert                Code = "alert"
+	CodePresenceOk           Code = "presence_ok"
+	CodePresenceEvent        Code = "presence_event"
+	CodeCursorEvent          Code = "cursor_event"
+	CodeChatEvent            Code = "chat_event"
+	CodeUndoOk               Code = "undo_ok"
+	CodeRedoOk               Code = "redo_ok"
+	CodeAddZoneOk            Code = "add_zone_ok"
+	CodeListZonesOk          Code = "list_zones_ok"
+	CodeScheduleEditOk       Code = "schedule_edit_ok"
+	CodeListScheduledEditsOk Code = "list_scheduled_edits_ok"
+	CodeCensusOk             Code = "census_ok"
+	CodeStatsHistoryOk       Code = "stats_history_ok"
+	CodeLocateOk             Code = "locate_ok"
+	CodeSubscribeStatsOk     Code = "subscribe_stats_ok"
+	CodeStats                Code = "stats"
+	CodeSession              Code = "session"
 )
 
+// IncomingMessage is a command from a client. ID is optional; when set, the
+// reply to the command carries it in its data as "request_id", so clients
+// can match replies to requests.
 type IncomingMessage struct {
+	ID      string      `json:"id,omitempty"`
 	Command Command     `json:"command"`
 	Data    MessageData `json:"data,omitempty"`
 }
 
+// IsEvent reports whether messages with the code are pushed to the client
+// rather than sent in reply to a command.
+func (c Code) IsEvent() bool {
+	switch c {
+	case CodeObserveEvent, CodeAlert, CodePresenceEvent, CodeCursorEvent, CodeChatEvent, CodeStats:
+		return true
+	default:
+		return false
+	}
+}
+
 type OutgoingMessage struct {
 	Code Code        `json:"code"`
 	Data MessageData `json:"data,omitempty"`
@@ -48,6 +105,23 @@ func (o OutgoingMessage) String() string {
 	return fmt.Sprintf("%s;%v\r\n", o.Code, string(jsonData))
 }
 
+// WithID returns a copy of the message carrying a request's id under
+// request_id, so it does not clash with data of the reply such as the
+// connection id of auth_ok. Messages are shared, such as ErrorInvalidData,
+// so the data is copied rather than changed.
+func (o OutgoingMessage) WithID(id string) OutgoingMessage {
+	if id == "" {
+		return o
+	}
+
+	data := make(MessageData, len(o.Data)+1)
+	for k, v := range o.Data {
+		data[k] = v
+	}
+	data["request_id"] = id
+	return OutgoingMessage{Code: o.Code, Data: data}
+}
+
 func NewOutgoingMessage(code Code, data MessageData) OutgoingMessage {
 	return OutgoingMessage{
 		Code: code,
@@ -55,6 +129,15 @@ func NewOutgoingMessage(code Code, data MessageData) OutgoingMessage {
 	}
 }
 
+// NewEditOkMessage acknowledges an edit with the generation it took effect
+// at and the number of cells it changed.
+func NewEditOkMessage(result game.EditResult) OutgoingMessage {
+	return NewOutgoingMessage(CodeOk, MessageData{
+		"generation": result.Generation,
+		"changed":    len(result.Changes),
+	})
+}
+
 func NewOutgoingErrorMessage(err string) OutgoingMessage {
 	return NewOutgoingMessage(CodeError, MessageData{"error": err})
 }
@@ -66,5 +149,7 @@ func NewOutgoingMessageChannel() chan OutgoingMessage {
 var ErrorUnknownCommand = NewOutgoingErrorMessage("unknown command")
 var ErrorInvalidData = NewOutgoingErrorMessage("invalid data")
 var ErrorGameNotFound = NewOutgoingErrorMessage("game not found")
+var ErrorAdminRequired = NewOutgoingErrorMessage("admin access required")
+var ErrorRateLimited = NewOutgoingErrorMessage("rate limit exceeded")
 
 var OkMessage = NewOutgoingMessage(CodeOk, MessageData{})
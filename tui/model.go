@@ -0,0 +1,294 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/henilmalaviya/gol/grid"
+	"github.com/henilmalaviya/golw/client"
+	"github.com/henilmalaviya/golw/game"
+)
+
+// statusLines is how many terminal rows the status bar takes.
+const statusLines = 2
+
+var (
+	statusStyle = lipgloss.NewStyle().Reverse(true)
+	helpStyle   = lipgloss.NewStyle().Faint(true)
+)
+
+const help = "arrows pan · hjkl cursor · space toggle · p/P pattern · enter place · m mode · c center · r sync · q quit"
+
+type (
+	syncedMsg struct {
+		bounds grid.Rectangle
+		result *client.SyncResult
+	}
+	eventMsg  client.Event
+	statsMsg  client.Stats
+	statusMsg string
+	errMsg    struct{ err error }
+)
+
+// model is the viewer's state. The view shows the cells from origin across
+// cols by rows characters, and is observed on the server so it stays live.
+type model struct {
+	ctx    context.Context
+	client *client.Client
+
+	origin     grid.Cell
+	cols, rows int
+	mode       renderMode
+	cells      map[grid.Cell]struct{}
+
+	cursor  grid.Cell
+	pattern int // index in game.CensusLibrary
+
+	stats  client.Stats
+	status string
+}
+
+func newModel(ctx context.Context, c *client.Client) model {
+	return model{
+		ctx:    ctx,
+		client: c,
+		cells:  make(map[grid.Cell]struct{}),
+	}
+}
+
+func (m model) Init() tea.Cmd {
+	return m.subscribeStats()
+}
+
+// bounds returns the region the view shows.
+func (m model) bounds() grid.Rectangle {
+	cw, ch := m.mode.cellsPerChar()
+	return *grid.NewRectangle(m.origin.X, m.origin.Y, m.origin.X+m.cols*cw-1, m.origin.Y+m.rows*ch-1)
+}
+
+// observe observes the view's region and syncs its cells.
+func (m model) observe() tea.Cmd {
+	if m.cols <= 0 || m.rows <= 0 {
+		return nil
+	}
+
+	bounds := m.bounds()
+	return func() tea.Msg {
+		if err := m.client.Observe(m.ctx, bounds, false); err != nil {
+			return errMsg{err}
+		}
+		result, err := m.client.Sync(m.ctx, bounds)
+		if err != nil {
+			return errMsg{err}
+		}
+		return syncedMsg{bounds: bounds, result: result}
+	}
+}
+
+func (m model) subscribeStats() tea.Cmd {
+	return func() tea.Msg {
+		if _, err := m.client.Do(m.ctx, "subscribe_stats", map[string]any{"interval": 1000}); err != nil {
+			return errMsg{err}
+		}
+		return nil
+	}
+}
+
+// edit runs an edit in the background and reports how it went.
+func (m model) edit(name string, run func() (client.EditResult, error)) tea.Cmd {
+	return func() tea.Msg {
+		result, err := run()
+		if err != nil {
+			return errMsg{err}
+		}
+		return statusMsg(fmt.Sprintf("%s changed %d cells at generation %d", name, result.Changed, result.Generation))
+	}
+}
+
+// patternCells returns the selected pattern with its top left at the
+// cursor.
+func (m model) patternCells() []grid.Cell {
+	cells := game.CensusLibrary[m.pattern].Cells
+	minX, minY := cells[0].X, cells[0].Y
+	for _, c := range cells {
+		minX, minY = min(minX, c.X), min(minY, c.Y)
+	}
+
+	out := make([]grid.Cell, len(cells))
+	for i, c := range cells {
+		out[i] = grid.Cell{X: m.cursor.X + c.X - minX, Y: m.cursor.Y + c.Y - minY}
+	}
+	return out
+}
+
+// clampCursor keeps the cursor inside the view.
+func (m *model) clampCursor() {
+	b := m.bounds()
+	m.cursor.X = min(max(m.cursor.X, b.X1), b.X2)
+	m.cursor.Y = min(max(m.cursor.Y, b.Y1), b.Y2)
+}
+
+// pan moves the view by a quarter of its size in each direction.
+func (m *model) pan(dx, dy int) tea.Cmd {
+	cw, ch := m.mode.cellsPerChar()
+	m.origin.X += dx * max(m.cols*cw/4, 1)
+	m.origin.Y += dy * max(m.rows*ch/4, 1)
+	m.clampCursor()
+	return m.observe()
+}
+
+// center puts the cell at (x, y) in the middle of the view.
+func (m *model) center(x, y int) tea.Cmd {
+	cw, ch := m.mode.cellsPerChar()
+	m.origin = grid.Cell{X: x - m.cols*cw/2, Y: y - m.rows*ch/2}
+	m.cursor = grid.Cell{X: x, Y: y}
+	m.clampCursor()
+	return m.observe()
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		first := m.cols == 0
+		m.cols, m.rows = msg.Width, max(msg.Height-statusLines, 1)
+		if first {
+			return m, m.center(0, 0)
+		}
+		m.clampCursor()
+		return m, m.observe()
+
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+
+	case syncedMsg:
+		// A pan may have happened since the sync was asked for.
+		if msg.bounds != m.bounds() {
+			return m, nil
+		}
+		m.cells = make(map[grid.Cell]struct{})
+		for _, c := range msg.result.Cells {
+			if msg.bounds.PointInside(c.X, c.Y) {
+				m.cells[c] = struct{}{}
+			}
+		}
+		m.stats = msg.result.Stats
+
+	case eventMsg:
+		switch msg.Type {
+		case client.EventSetCell:
+			m.cells[msg.Cell] = struct{}{}
+		case client.EventClearCell:
+			delete(m.cells, msg.Cell)
+		case client.EventTick:
+			for _, c := range msg.Born {
+				m.cells[c] = struct{}{}
+			}
+			for _, c := range msg.Died {
+				delete(m.cells, c)
+			}
+		case client.EventReconnected:
+			m.status = "reconnected"
+			return m, tea.Batch(m.observe(), m.subscribeStats())
+		}
+
+	case statsMsg:
+		m.stats = client.Stats(msg)
+
+	case statusMsg:
+		m.status = string(msg)
+
+	case errMsg:
+		m.status = "error: " + msg.err.Error()
+	}
+	return m, nil
+}
+
+func (m model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "up":
+		return m, m.pan(0, -1)
+	case "down":
+		return m, m.pan(0, 1)
+	case "left":
+		return m, m.pan(-1, 0)
+	case "right":
+		return m, m.pan(1, 0)
+	case "h":
+		m.cursor.X--
+	case "l":
+		m.cursor.X++
+	case "k":
+		m.cursor.Y--
+	case "j":
+		m.cursor.Y++
+	case " ":
+		cells := []grid.Cell{m.cursor}
+		return m, m.edit("toggle", func() (client.EditResult, error) {
+			return m.client.ToggleCells(m.ctx, cells)
+		})
+	case "p":
+		m.pattern = (m.pattern + 1) % len(game.CensusLibrary)
+	case "P":
+		m.pattern = (m.pattern + len(game.CensusLibrary) - 1) % len(game.CensusLibrary)
+	case "enter":
+		cells := m.patternCells()
+		name := game.CensusLibrary[m.pattern].Name
+		return m, m.edit(name, func() (client.EditResult, error) {
+			return m.client.SetCells(m.ctx, cells)
+		})
+	case "m":
+		if m.mode == modeHalfBlock {
+			m.mode = modeBraille
+		} else {
+			m.mode = modeHalfBlock
+		}
+		return m, m.center(m.cursor.X, m.cursor.Y)
+	case "c":
+		return m, m.center(0, 0)
+	case "r":
+		return m, m.observe()
+	}
+
+	// The cursor pans the view when it leaves it.
+	b := m.bounds()
+	if !b.PointInside(m.cursor.X, m.cursor.Y) {
+		return m, m.center(m.cursor.X, m.cursor.Y)
+	}
+	return m, nil
+}
+
+func (m model) View() string {
+	if m.cols == 0 {
+		return "connecting..."
+	}
+
+	b := m.bounds()
+	status := fmt.Sprintf(" gen %d · pop %d · view %v · cursor (%d, %d) · %s · %s",
+		m.stats.Generation, m.stats.Population, b.ToNestedArray(), m.cursor.X, m.cursor.Y,
+		game.CensusLibrary[m.pattern].Name, m.mode)
+	if m.stats.Period > 0 {
+		status += fmt.Sprintf(" · period %d", m.stats.Period)
+	}
+	status += strings.Repeat(" ", max(m.cols-lipgloss.Width(status), 0))
+
+	line := help
+	if m.status != "" {
+		line = m.status
+	}
+
+	return render(m.cells, m.origin, m.cols, m.rows, m.mode, m.cursor) + "\n" +
+		statusStyle.Render(truncate(status, m.cols)) + "\n" +
+		helpStyle.Render(truncate(line, m.cols))
+}
+
+func truncate(s string, width int) string {
+	if r := []rune(s); len(r) > width {
+		return string(r[:width])
+	}
+	return s
+}
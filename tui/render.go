@@ -0,0 +1,91 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/henilmalaviya/gol/grid"
+)
+
+// renderMode is how cells are packed into terminal characters.
+type renderMode int
+
+const (
+	// modeHalfBlock draws two cells per character, one above the other.
+	modeHalfBlock renderMode = iota
+	// modeBraille draws eight cells per character, two wide and four high.
+	modeBraille
+)
+
+func (m renderMode) String() string {
+	if m == modeBraille {
+		return "braille"
+	}
+	return "half-block"
+}
+
+// cellsPerChar returns how many cells a character holds across and down.
+func (m renderMode) cellsPerChar() (int, int) {
+	if m == modeBraille {
+		return 2, 4
+	}
+	return 1, 2
+}
+
+// halfBlocks is the character for a top and a bottom cell.
+var halfBlocks = [2][2]rune{{' ', '▄'}, {'▀', '█'}}
+
+// brailleDots is the bit of each dot of a braille character, by row and
+// column.
+var brailleDots = [4][2]rune{{0x01, 0x08}, {0x02, 0x10}, {0x04, 0x20}, {0x40, 0x80}}
+
+const brailleBlank = 0x2800
+
+var cursorStyle = lipgloss.NewStyle().Reverse(true)
+
+// render draws the cells of a cols by rows character area whose top left
+// cell is origin, highlighting the character that holds the cursor.
+func render(cells map[grid.Cell]struct{}, origin grid.Cell, cols, rows int, mode renderMode, cursor grid.Cell) string {
+	cw, ch := mode.cellsPerChar()
+	alive := func(x, y int) bool {
+		_, ok := cells[grid.Cell{X: origin.X + x, Y: origin.Y + y}]
+		return ok
+	}
+
+	var b strings.Builder
+	for row := range rows {
+		for col := range cols {
+			var r rune
+			if mode == modeBraille {
+				r = brailleBlank
+				for dy := range ch {
+					for dx := range cw {
+						if alive(col*cw+dx, row*ch+dy) {
+							r |= brailleDots[dy][dx]
+						}
+					}
+				}
+			} else {
+				r = halfBlocks[b2i(alive(col, row*2))][b2i(alive(col, row*2+1))]
+			}
+
+			cx, cy := cursor.X-origin.X, cursor.Y-origin.Y
+			if cx >= col*cw && cx < (col+1)*cw && cy >= row*ch && cy < (row+1)*ch {
+				b.WriteString(cursorStyle.Render(string(r)))
+			} else {
+				b.WriteRune(r)
+			}
+		}
+		if row < rows-1 {
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+func b2i(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
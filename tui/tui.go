@@ -0,0 +1,64 @@
+// Package tui is a terminal viewer for the game server. It observes the
+// part of the world that fits the terminal, drawn with half-block or braille
+// characters, and lets the user pan, toggle cells and place patterns.
+package tui
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/henilmalaviya/golw/client"
+)
+
+// Run connects to the server's WebSocket endpoint at url and shows the
+// viewer until the user quits.
+func Run(ctx context.Context, url string, opts client.Options) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Stats are pushed outside of the observe events, so the client hands
+	// them over here. Stale ones are dropped rather than blocking it.
+	stats := make(chan client.Stats, 1)
+	opts.OnMessage = func(msg client.Message) {
+		if msg.Code != "stats" {
+			return
+		}
+		var data struct {
+			Stats client.Stats `json:"stats"`
+		}
+		if msg.Decode(&data) != nil {
+			return
+		}
+		select {
+		case stats <- data.Stats:
+		default:
+		}
+	}
+
+	c, err := client.Dial(ctx, url, opts)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	program := tea.NewProgram(newModel(ctx, c), tea.WithAltScreen(), tea.WithContext(ctx))
+
+	go func() {
+		for event := range c.Events() {
+			program.Send(eventMsg(event))
+		}
+	}()
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case s := <-stats:
+				program.Send(statsMsg(s))
+			}
+		}
+	}()
+
+	_, err = program.Run()
+	return err
+}
@@ -57,3 +57,17 @@ func GetCellsArrayFromData(data gjson.Result, key string) ([]grid.Cell, bool) {
 	})
 	return cellsArray, len(cellsArray) > 0
 }
+
+func GetPointFromData(data gjson.Result, key string) (int, int, bool) {
+	point := data.Get(key)
+	if !point.IsArray() {
+		return 0, 0, false
+	}
+
+	coords := point.Array()
+	if len(coords) != 2 || coords[0].Type != gjson.Number || coords[1].Type != gjson.Number {
+		return 0, 0, false
+	}
+
+	return int(coords[0].Int()), int(coords[1].Int()), true
+}
@@ -34,6 +34,12 @@ func parseLogLevel(level string) log.Level {
 	}
 }
 
+// SetLogLevel changes the level of the logger, for example after the
+// settings were reloaded.
+func SetLogLevel(level string) {
+	logger.SetLevel(parseLogLevel(level))
+}
+
 func init() {
 	logger = log.NewWithOptions(os.Stdout, log.Options{
 		ReportTimestamp: true,
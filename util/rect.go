@@ -1,7 +1,10 @@
 package util
 
 import (
+	"fmt"
 	"math"
+	"strconv"
+	"strings"
 
 	"github.com/henilmalaviya/gol/grid"
 )
@@ -15,3 +18,22 @@ func DiagonalLength(rect grid.Rectangle) float64 {
 
 	return math.Sqrt(w2 + h2)
 }
+
+// ParseRectangle parses a rectangle written as "x1,y1,x2,y2".
+func ParseRectangle(value string) (grid.Rectangle, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 4 {
+		return grid.Rectangle{}, fmt.Errorf("rectangle %q must have the form x1,y1,x2,y2", value)
+	}
+
+	var coords [4]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return grid.Rectangle{}, fmt.Errorf("invalid coordinate %q in rectangle %q", part, value)
+		}
+		coords[i] = n
+	}
+
+	return *grid.NewRectangle(coords[0], coords[1], coords[2], coords[3]), nil
+}
@@ -0,0 +1,367 @@
+// Browser viewer for the Game of Life server. It observes the part of the
+// world on screen over the WebSocket protocol, draws it on a canvas and
+// sends edits with set_cells and clear_cells.
+"use strict";
+
+const wsEndpoint = document.querySelector('meta[name="ws-endpoint"]').content;
+const maxRegionSize = Number(document.querySelector('meta[name="max-region-size"]').content) || 1000;
+
+const canvas = document.getElementById("grid");
+const ctx = canvas.getContext("2d");
+
+const state = {
+  socket: null,
+  nextId: 1,
+  pending: new Map(), // request id -> {resolve, reject}
+  reconnectDelay: 500,
+
+  cells: new Set(), // "x,y" of the live cells in the observed region
+  stroke: new Set(), // cells of the edit being drawn, not sent yet
+  bounds: null, // observed region as [[x1, y1], [x2, y2]]
+
+  // The view's top left corner in cells and its size in pixels per cell.
+  view: { x: -40, y: -25, scale: 12 },
+  tool: "draw",
+  pointer: null, // the drag in progress
+};
+
+const key = (x, y) => x + "," + y;
+
+/* ------------------------------- Protocol ------------------------------- */
+
+// parseFrame splits a "code;{json}\r\n" frame.
+function parseFrame(frame) {
+  const i = frame.indexOf(";");
+  return { code: frame.slice(0, i), data: JSON.parse(frame.slice(i + 1)) };
+}
+
+// request sends a command and resolves with the data of its reply, or
+// rejects with the server's error.
+function request(command, data) {
+  return new Promise((resolve, reject) => {
+    if (!state.socket || state.socket.readyState !== WebSocket.OPEN) {
+      reject(new Error("not connected"));
+      return;
+    }
+    const id = "w" + state.nextId++;
+    state.pending.set(id, { resolve, reject });
+    state.socket.send(JSON.stringify({ id, command, data }));
+  });
+}
+
+const eventCodes = new Set(["observe_event", "alert", "presence_event", "cursor_event", "chat_event", "stats"]);
+
+function handleMessage(msg) {
+  const id = msg.data.request_id;
+  if (!eventCodes.has(msg.code) && id && state.pending.has(id)) {
+    const { resolve, reject } = state.pending.get(id);
+    state.pending.delete(id);
+    if (msg.code === "error") {
+      reject(new Error(msg.data.error));
+    } else {
+      resolve(msg.data);
+    }
+    return;
+  }
+
+  switch (msg.code) {
+    case "observe_event":
+      applyEvent(msg.data.event, msg.data.data);
+      break;
+    case "stats":
+      showStats(msg.data.stats, msg.data.users);
+      break;
+  }
+}
+
+function applyEvent(event, data) {
+  switch (event) {
+    case "set_cell":
+      state.cells.add(key(data.cell[0], data.cell[1]));
+      break;
+    case "clear_cell":
+      state.cells.delete(key(data.cell[0], data.cell[1]));
+      break;
+    case "tick":
+      for (const [x, y] of data.bornCells) state.cells.add(key(x, y));
+      for (const [x, y] of data.diedCells) state.cells.delete(key(x, y));
+      break;
+    default:
+      return;
+  }
+  requestDraw();
+}
+
+function connect() {
+  const scheme = location.protocol === "https:" ? "wss:" : "ws:";
+  const socket = new WebSocket(scheme + "//" + location.host + wsEndpoint);
+  state.socket = socket;
+
+  socket.onopen = () => {
+    state.reconnectDelay = 500;
+    setConnection(true);
+    request("subscribe_stats", { interval: 1000 }).catch(showError);
+    state.bounds = null;
+    observe();
+  };
+
+  socket.onmessage = (e) => {
+    try {
+      handleMessage(parseFrame(e.data));
+    } catch (err) {
+      console.error("bad frame", e.data, err);
+    }
+  };
+
+  socket.onclose = () => {
+    setConnection(false);
+    for (const { reject } of state.pending.values()) reject(new Error("disconnected"));
+    state.pending.clear();
+    setTimeout(connect, state.reconnectDelay);
+    state.reconnectDelay = Math.min(state.reconnectDelay * 2, 30000);
+  };
+}
+
+/* ----------------------------- Observation ------------------------------ */
+
+// visibleBounds returns the cells on screen.
+function visibleBounds() {
+  const { x, y, scale } = state.view;
+  return [
+    [Math.floor(x), Math.floor(y)],
+    [Math.ceil(x + canvas.width / scale), Math.ceil(y + canvas.height / scale)],
+  ];
+}
+
+let observeTimer = null;
+
+// observe observes and syncs the cells on screen once the view settles.
+function observe() {
+  clearTimeout(observeTimer);
+  observeTimer = setTimeout(async () => {
+    const bounds = visibleBounds();
+    if (JSON.stringify(bounds) === JSON.stringify(state.bounds)) return;
+
+    try {
+      await request("observe", { bounds });
+      const reply = await request("sync", { bounds });
+      const [[x1, y1], [x2, y2]] = bounds;
+
+      state.bounds = bounds;
+      state.cells = new Set();
+      for (const [x, y] of reply.cells) {
+        if (x >= x1 && x <= x2 && y >= y1 && y <= y2) state.cells.add(key(x, y));
+      }
+      showStats(reply.stats);
+      document.getElementById("view").textContent = `${x1},${y1} → ${x2},${y2}`;
+      requestDraw();
+    } catch (err) {
+      showError(err);
+    }
+  }, 150);
+}
+
+/* ------------------------------- Drawing -------------------------------- */
+
+let drawPending = false;
+
+function requestDraw() {
+  if (drawPending) return;
+  drawPending = true;
+  requestAnimationFrame(() => {
+    drawPending = false;
+    draw();
+  });
+}
+
+function draw() {
+  const { x: vx, y: vy, scale } = state.view;
+  ctx.fillStyle = "#111";
+  ctx.fillRect(0, 0, canvas.width, canvas.height);
+
+  if (scale >= 8) {
+    ctx.strokeStyle = "#222";
+    ctx.lineWidth = 1;
+    ctx.beginPath();
+    for (let x = Math.ceil(vx); x < vx + canvas.width / scale; x++) {
+      const px = Math.round((x - vx) * scale) + 0.5;
+      ctx.moveTo(px, 0);
+      ctx.lineTo(px, canvas.height);
+    }
+    for (let y = Math.ceil(vy); y < vy + canvas.height / scale; y++) {
+      const py = Math.round((y - vy) * scale) + 0.5;
+      ctx.moveTo(0, py);
+      ctx.lineTo(canvas.width, py);
+    }
+    ctx.stroke();
+  }
+
+  const gap = scale >= 4 ? 1 : 0;
+  const fill = (cells, color) => {
+    ctx.fillStyle = color;
+    for (const k of cells) {
+      const [x, y] = k.split(",").map(Number);
+      ctx.fillRect(Math.round((x - vx) * scale) + gap, Math.round((y - vy) * scale) + gap, Math.max(scale - gap, 1), Math.max(scale - gap, 1));
+    }
+  };
+  fill(state.cells, "#e8e8e8");
+  fill(state.stroke, state.tool === "erase" ? "#a33" : "#3a6");
+
+  // Mark the origin so it can be found again.
+  ctx.strokeStyle = "#3a6";
+  ctx.strokeRect(Math.round(-vx * scale) + 0.5, Math.round(-vy * scale) + 0.5, scale, scale);
+}
+
+function resize() {
+  canvas.width = window.innerWidth;
+  canvas.height = window.innerHeight;
+  setScale(state.view.scale, canvas.width / 2, canvas.height / 2);
+}
+
+// minScale keeps the view within the largest region the server lets us
+// observe.
+function minScale() {
+  return Math.hypot(canvas.width, canvas.height) / (maxRegionSize - 4);
+}
+
+// setScale zooms around the pixel (px, py).
+function setScale(scale, px, py) {
+  const view = state.view;
+  const next = Math.min(Math.max(scale, minScale(), 1), 64);
+  view.x += px / view.scale - px / next;
+  view.y += py / view.scale - py / next;
+  view.scale = next;
+  requestDraw();
+  observe();
+}
+
+function panBy(dx, dy) {
+  state.view.x += dx / state.view.scale;
+  state.view.y += dy / state.view.scale;
+  requestDraw();
+  observe();
+}
+
+/* -------------------------------- Input --------------------------------- */
+
+function cellAt(e) {
+  return [Math.floor(state.view.x + e.offsetX / state.view.scale), Math.floor(state.view.y + e.offsetY / state.view.scale)];
+}
+
+function setTool(tool) {
+  state.tool = tool;
+  for (const button of document.querySelectorAll("[data-tool]")) {
+    button.classList.toggle("active", button.dataset.tool === tool);
+  }
+  canvas.classList.toggle("panning", tool === "pan");
+}
+
+async function sendStroke() {
+  if (state.stroke.size === 0) return;
+  const cells = [...state.stroke].map((k) => k.split(",").map(Number));
+  const command = state.tool === "erase" ? "clear_cells" : "set_cells";
+  try {
+    await request(command, { cells });
+  } catch (err) {
+    showError(err);
+  } finally {
+    state.stroke.clear();
+    requestDraw();
+  }
+}
+
+canvas.addEventListener("contextmenu", (e) => e.preventDefault());
+
+canvas.addEventListener("pointerdown", (e) => {
+  canvas.setPointerCapture(e.pointerId);
+  const panning = e.button !== 0 || state.tool === "pan";
+  state.pointer = { panning, x: e.clientX, y: e.clientY };
+  if (!panning) {
+    state.stroke.add(key(...cellAt(e)));
+    requestDraw();
+  }
+});
+
+canvas.addEventListener("pointermove", (e) => {
+  const pointer = state.pointer;
+  if (!pointer) return;
+  if (pointer.panning) {
+    panBy(pointer.x - e.clientX, pointer.y - e.clientY);
+    pointer.x = e.clientX;
+    pointer.y = e.clientY;
+  } else {
+    state.stroke.add(key(...cellAt(e)));
+    requestDraw();
+  }
+});
+
+canvas.addEventListener("pointerup", () => {
+  if (state.pointer && !state.pointer.panning) sendStroke();
+  state.pointer = null;
+});
+
+canvas.addEventListener("wheel", (e) => {
+  e.preventDefault();
+  setScale(state.view.scale * Math.pow(1.1, -Math.sign(e.deltaY)), e.offsetX, e.offsetY);
+}, { passive: false });
+
+window.addEventListener("keydown", (e) => {
+  const step = 50;
+  switch (e.key) {
+    case "ArrowUp": panBy(0, -step); break;
+    case "ArrowDown": panBy(0, step); break;
+    case "ArrowLeft": panBy(-step, 0); break;
+    case "ArrowRight": panBy(step, 0); break;
+    case "+": case "=": setScale(state.view.scale * 1.25, canvas.width / 2, canvas.height / 2); break;
+    case "-": setScale(state.view.scale / 1.25, canvas.width / 2, canvas.height / 2); break;
+    case "d": setTool("draw"); break;
+    case "e": setTool("erase"); break;
+    case "p": setTool("pan"); break;
+    case "h": home(); break;
+    default: return;
+  }
+  e.preventDefault();
+});
+
+for (const button of document.querySelectorAll("[data-tool]")) {
+  button.addEventListener("click", () => setTool(button.dataset.tool));
+}
+document.getElementById("home").addEventListener("click", home);
+
+function home() {
+  state.view.x = -canvas.width / state.view.scale / 2;
+  state.view.y = -canvas.height / state.view.scale / 2;
+  requestDraw();
+  observe();
+}
+
+/* -------------------------------- Panel --------------------------------- */
+
+function setConnection(online) {
+  const el = document.getElementById("connection");
+  el.textContent = online ? "Connected" : "Reconnecting…";
+  el.className = online ? "online" : "offline";
+}
+
+function showStats(stats, users) {
+  if (!stats) return;
+  document.getElementById("generation").textContent = stats.generation.toLocaleString();
+  document.getElementById("population").textContent = stats.population.toLocaleString();
+  document.getElementById("tick-rate").textContent = stats.tick_rate ? stats.tick_rate.toFixed(1) + "/s" : "paused";
+  document.getElementById("period").textContent = stats.period ? `${stats.period} since ${stats.stabilized_at}` : "-";
+  if (users !== undefined) document.getElementById("users").textContent = users;
+}
+
+let messageTimer = null;
+
+function showError(err) {
+  const el = document.getElementById("message");
+  el.textContent = err.message || String(err);
+  clearTimeout(messageTimer);
+  messageTimer = setTimeout(() => (el.textContent = ""), 4000);
+}
+
+window.addEventListener("resize", resize);
+resize();
+home();
+connect();
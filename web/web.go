@@ -0,0 +1,54 @@
+// Package web serves the built-in browser viewer. It is plain HTML and
+// JavaScript talking to the WebSocket endpoint, embedded in the binary.
+package web
+
+import (
+	"bytes"
+	"embed"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"time"
+)
+
+//go:embed static
+var static embed.FS
+
+// Config is what the viewer needs to know about the server.
+type Config struct {
+	// WSEndpoint is the path of the WebSocket endpoint.
+	WSEndpoint string
+	// MaxRegionSize is the largest diagonal the server lets a client
+	// observe.
+	MaxRegionSize int
+}
+
+// Handler serves the viewer at the root of its path, with the files it
+// loads next to it.
+func Handler(config Config) (http.Handler, error) {
+	files, err := fs.Sub(static, "static")
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := template.ParseFS(files, "index.html")
+	if err != nil {
+		return nil, err
+	}
+	var index bytes.Buffer
+	if err := page.Execute(&index, config); err != nil {
+		return nil, err
+	}
+
+	fileServer := http.FileServerFS(files)
+	started := time.Now()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/", "/index.html":
+			http.ServeContent(w, r, "index.html", started, bytes.NewReader(index.Bytes()))
+		default:
+			fileServer.ServeHTTP(w, r)
+		}
+	}), nil
+}